@@ -2,64 +2,185 @@ package api
 
 import (
 	"base_structure/docs"
+	"base_structure/src/api/dto"
 	"base_structure/src/api/middlewares"
 	"base_structure/src/api/routers"
 	"base_structure/src/api/validations"
 	"base_structure/src/config"
+	"base_structure/src/pkg/clientip"
+	"base_structure/src/pkg/health"
+	"base_structure/src/pkg/lifecycle"
 	"base_structure/src/pkg/logging"
+	"context"
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
 	"github.com/go-playground/validator/v10"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"net"
+	"net/http"
 	"os"
+	"time"
 )
 
-func InitServer(cfg *config.Config) {
+const defaultShutdownTimeout = 30 * time.Second
+
+// InitServer serves the API until ctx is done, then shuts down gracefully: it reports not ready, waits
+// server.drainDelay seconds for load balancers to notice, and lets in-flight requests finish within
+// server.shutdownTimeout seconds. Background work started for the routes is stopped through hooks. /readyz runs
+// checks.
+func InitServer(ctx context.Context, cfg *config.Config, hooks *lifecycle.Registry, checks *health.Registry) error {
 	logger := logging.NewLogger(cfg)
-	defer func(logger logging.Logger) {
-		err := logger.Sync()
-		if err != nil {
-			logger.Fatal(logging.Internal, logging.Closing, "error on syncing logger", nil)
-		}
-	}(logger)
 	appEnv := os.Getenv("APP_ENV")
 	gin.SetMode(cfg.Server.RunMode)
 	r := gin.New()
+	ConfigureProxies(r, cfg, logger)
+	r.Use(middlewares.RequestId())
+	r.Use(middlewares.ResponseMode(cfg))
+	r.Use(middlewares.Language(cfg))
+	r.Use(middlewares.Metrics())
 	if appEnv == "development" {
-		r.Use(gin.Logger(), gin.CustomRecovery(middlewares.ErrorHandler))
+		r.Use(gin.CustomRecovery(middlewares.ErrorHandler))
 	} else {
-		r.Use(gin.Logger(), gin.Recovery())
+		r.Use(gin.Recovery())
 	}
+	r.Use(middlewares.ClientIP(cfg))
+	r.Use(middlewares.Tracing())
 	r.Use(middlewares.Cors(cfg))
-	r.Use(middlewares.StructuredLogger(logger))
+	r.Use(middlewares.StructuredLogger(cfg, logger))
+	r.Use(middlewares.RequestInfo())
 	RegisterValidators(logger)
-	RegisterRoutes(r, cfg)
+	workers, stopWorkers := context.WithCancel(context.Background())
+	hooks.OnStop("route workers", func(context.Context) error {
+		stopWorkers()
+		return nil
+	})
+	routers.Health(r.Group(""), checks, hooks)
+	routers.Metrics(r.Group(""))
+	RegisterRoutes(workers, r, cfg)
 	RegisterSwagger(r, cfg)
-	err := r.Run(fmt.Sprintf(":%s", cfg.Server.Port))
+
+	srv := &http.Server{
+		Addr:              fmt.Sprintf(":%s", cfg.Server.Port),
+		Handler:           r,
+		ReadTimeout:       cfg.Server.ReadTimeout * time.Second,
+		ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout * time.Second,
+		WriteTimeout:      cfg.Server.WriteTimeout * time.Second,
+		IdleTimeout:       cfg.Server.IdleTimeout * time.Second,
+	}
+	ln, err := net.Listen("tcp", srv.Addr)
 	if err != nil {
-		logger.Fatal(logging.Internal, logging.Api, "error on running router", nil)
-		return
+		logger.Error(logging.Internal, logging.Api, fmt.Sprintf("error on listening on %s: %v", srv.Addr, err), nil)
+		return err
 	}
+	served := make(chan error, 1)
+	go func() {
+		served <- srv.Serve(ln)
+	}()
+	hooks.SetReady(true)
+	logger.Info(logging.Internal, logging.StartUp, fmt.Sprintf("listening on %s", srv.Addr), nil)
+
+	select {
+	case err = <-served:
+		hooks.SetReady(false)
+		logger.Error(logging.Internal, logging.Api, fmt.Sprintf("error on running server: %v", err), nil)
+		return err
+	case <-ctx.Done():
+	}
+	hooks.SetReady(false)
+	logger.Info(logging.Internal, logging.Closing, "shutting down, draining connections", nil)
+	time.Sleep(cfg.Server.DrainDelay * time.Second)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), ShutdownTimeout(cfg))
+	defer cancel()
+	if err = srv.Shutdown(shutdownCtx); err != nil {
+		logger.Error(logging.Internal, logging.Closing, fmt.Sprintf("error on draining connections: %v", err), nil)
+		_ = srv.Close()
+		return err
+	}
+	logger.Info(logging.Internal, logging.Closing, "server stopped", nil)
+	return nil
+}
+
+// ShutdownTimeout is the deadline for draining connections, and again for running the stop hooks.
+func ShutdownTimeout(cfg *config.Config) time.Duration {
+	if cfg.Server.ShutdownTimeout <= 0 {
+		return defaultShutdownTimeout
+	}
+	return cfg.Server.ShutdownTimeout * time.Second
 }
 
-func RegisterRoutes(r *gin.Engine, cfg *config.Config) {
+func RegisterRoutes(ctx context.Context, r *gin.Engine, cfg *config.Config) {
 	api := r.Group("/api")
 	v1 := api.Group("/v1")
 	{
 		//User
-		users := v1.Group("/users")
+		users := v1.Group("/users", middlewares.RouteRateLimit(cfg, "users"))
 
 		//User
-		routers.User(users, cfg)
+		routers.User(ctx, users, cfg)
+
+		//WebAuthn
+		webAuthn := users.Group("/webauthn")
+		routers.WebAuthn(webAuthn, cfg)
+
+		//OAuth
+		oauth := users.Group("/oauth")
+		routers.OAuth(oauth, cfg)
+
+		//AuditLog
+		auditLogs := v1.Group("/audit-logs")
+		routers.AuditLog(auditLogs, cfg)
+
+		//Debug
+		debug := v1.Group("/debug")
+		routers.Debug(debug, cfg)
 	}
 }
 
+// ConfigureProxies makes gin's own client IP resolution (c.ClientIP) agree with helper.ClientIP. Gin can't parse the
+// Forwarded header, so that one is left to the ClientIP middleware.
+func ConfigureProxies(r *gin.Engine, cfg *config.Config, logger logging.Logger) {
+	resolver, err := clientip.NewResolver(cfg.Server.TrustedProxies, cfg.Server.TrustedHeaders)
+	if err == nil {
+		err = r.SetTrustedProxies(cfg.Server.TrustedProxies)
+	}
+	if err != nil {
+		logger.Fatal(logging.Internal, logging.StartUp, fmt.Sprintf("invalid proxy configuration: %v", err), nil)
+	}
+	r.RemoteIPHeaders = nil
+	for _, h := range resolver.Headers() {
+		if http.CanonicalHeaderKey(h) != clientip.Forwarded {
+			r.RemoteIPHeaders = append(r.RemoteIPHeaders, h)
+		}
+	}
+}
+
+// RegisterValidators normalizes the normalize tagged fields of every bound request, names fields in validation errors
+// after their json tag and adds the custom validations. A request with an unknown normalizer stops the start up.
 func RegisterValidators(logger logging.Logger) {
+	err := validations.CheckNormalizeTags(
+		dto.GetOtpRequest{},
+		dto.RegisterByUsernameRequest{},
+		dto.RegisterLoginByMobileRequest{},
+		dto.LoginByUsernameRequest{},
+		dto.LogoutRequest{},
+		dto.UpdateLogLevelRequest{},
+		dto.AuditLogFilter{},
+		dto.OAuthCallbackRequest{},
+		dto.WebAuthnFinishRegistrationRequest{},
+		dto.WebAuthnBeginLoginRequest{},
+		dto.WebAuthnFinishLoginRequest{},
+	)
+	if err != nil {
+		logger.Fatal(logging.Validation, logging.NormalizeTags, err.Error(), nil)
+		return
+	}
+	binding.Validator = validations.NewNormalizingValidator(binding.Validator)
 	val, ok := binding.Validator.Engine().(*validator.Validate)
 	if ok {
-		err := val.RegisterValidation("ir_mobile", validations.IranianMobileNumberValidator, true)
+		val.RegisterTagNameFunc(validations.FieldName)
+		err = val.RegisterValidation("ir_mobile", validations.IranianMobileNumberValidator, true)
 		if err != nil {
 			logger.Fatal(
 				logging.Validation,
@@ -0,0 +1,39 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type AuditLogFilter struct {
+	ActorId    *int      `form:"actorId"`
+	Action     string    `form:"action"`
+	TargetType string    `form:"targetType"`
+	TargetId   string    `form:"targetId"`
+	From       time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To         time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	PageNumber int       `form:"pageNumber" binding:"omitempty,min=1"`
+	PageSize   int       `form:"pageSize" binding:"omitempty,min=1,max=100"`
+}
+
+type AuditLogResponse struct {
+	Id         uint            `json:"id"`
+	CreatedAt  time.Time       `json:"createdAt"`
+	ActorId    int             `json:"actorId"`
+	Action     string          `json:"action"`
+	TargetType string          `json:"targetType"`
+	TargetId   string          `json:"targetId"`
+	Ip         string          `json:"ip"`
+	UserAgent  string          `json:"userAgent"`
+	RequestId  string          `json:"requestId"`
+	Before     json.RawMessage `json:"before,omitempty" swaggertype:"object"`
+	After      json.RawMessage `json:"after,omitempty" swaggertype:"object"`
+}
+
+type PagedList[T any] struct {
+	PageNumber int   `json:"pageNumber"`
+	PageSize   int   `json:"pageSize"`
+	TotalRows  int64 `json:"totalRows"`
+	TotalPages int   `json:"totalPages"`
+	Items      []T   `json:"items"`
+}
@@ -0,0 +1,13 @@
+package dto
+
+// UpdateLogLevelRequest changes the global level, unless Level is empty, and the levels of the given categories. An
+// empty category level removes the override, so the category follows the global level again.
+type UpdateLogLevelRequest struct {
+	Level      string            `json:"level" binding:"omitempty,oneof=debug info warn error fatal"`
+	Categories map[string]string `json:"categories" example:"Postgres:debug"`
+}
+
+type LogLevelResponse struct {
+	Level      string            `json:"level"`
+	Categories map[string]string `json:"categories"`
+}
@@ -0,0 +1,10 @@
+package dto
+
+type OAuthAuthUrlResponse struct {
+	AuthUrl string `json:"authUrl"`
+}
+
+type OAuthCallbackRequest struct {
+	Code  string `form:"code" binding:"required"`
+	State string `form:"state" binding:"required"`
+}
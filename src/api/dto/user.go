@@ -1,7 +1,7 @@
 package dto
 
 type GetOtpRequest struct {
-	MobileNumber string `json:"mobileNumber" binding:"required,ir_mobile"`
+	MobileNumber string `json:"mobileNumber" binding:"required,ir_mobile" normalize:"digits,zerowidth"`
 }
 
 type TokenDetail struct {
@@ -12,16 +12,16 @@ type TokenDetail struct {
 }
 
 type RegisterByUsernameRequest struct {
-	FirstName string `json:"firstName" binding:"required,min=3"`
-	LastName  string `json:"lastName" binding:"required,min=3"`
+	FirstName string `json:"firstName" binding:"required,min=3" normalize:"letters"`
+	LastName  string `json:"lastName" binding:"required,min=3" normalize:"letters"`
 	Username  string `json:"username" binding:"required,min=5"`
 	Email     string `json:"email" binding:"min=6,email"`
 	Password  string `json:"password" binding:"required,password,min=6"`
 }
 
 type RegisterLoginByMobileRequest struct {
-	MobileNumber string `json:"mobileNumber" binding:"required,ir_mobile,min=11,max=11"`
-	Otp          string `json:"otp" binding:"required,min=6,max=6"`
+	MobileNumber string `json:"mobileNumber" binding:"required,ir_mobile,min=11,max=11" normalize:"digits,zerowidth"`
+	Otp          string `json:"otp" binding:"required,min=6,max=6" normalize:"digits,zerowidth"`
 }
 
 type LoginByUsernameRequest struct {
@@ -0,0 +1,25 @@
+package dto
+
+import (
+	"encoding/json"
+	"github.com/go-webauthn/webauthn/protocol"
+)
+
+type WebAuthnFinishRegistrationRequest struct {
+	Name       string          `json:"name" binding:"max=64"`
+	Credential json.RawMessage `json:"credential" binding:"required" swaggertype:"object"`
+}
+
+type WebAuthnBeginLoginRequest struct {
+	Username string `json:"username" binding:"required,min=5"`
+}
+
+type WebAuthnBeginLoginResponse struct {
+	CeremonyId string                        `json:"ceremonyId"`
+	Options    *protocol.CredentialAssertion `json:"options" swaggertype:"object"`
+}
+
+type WebAuthnFinishLoginRequest struct {
+	CeremonyId string          `json:"ceremonyId" binding:"required"`
+	Credential json.RawMessage `json:"credential" binding:"required" swaggertype:"object"`
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"base_structure/src/api/dto"
+	"base_structure/src/api/helper"
+	"base_structure/src/config"
+	"base_structure/src/services"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type AuditLogHandler struct {
+	cfg             *config.Config
+	auditLogService services.AuditLogServiceIface
+}
+
+func NewAuditLogHandler(cfg *config.Config) *AuditLogHandler {
+	return &AuditLogHandler{
+		cfg:             cfg,
+		auditLogService: services.NewAuditLogService(cfg),
+	}
+}
+
+func NewAuditLogHandlerWithSvc(cfg *config.Config, svc services.AuditLogServiceIface) *AuditLogHandler {
+	return &AuditLogHandler{
+		cfg:             cfg,
+		auditLogService: svc,
+	}
+}
+
+// List
+// @Summary      Query audit log
+// @Description  Returns audit records, newest first. All filters are optional; from/to are RFC 3339 timestamps.
+// @Tags         AuditLog
+// @Produce      json
+// @Param        actorId     query     int     false  "Actor user id (0 = system, -1 = unknown)"
+// @Param        action      query     string  false  "Action, e.g. login_failed"
+// @Param        targetType  query     string  false  "Target type"
+// @Param        targetId    query     string  false  "Target id"
+// @Param        from        query     string  false  "Created at or after"
+// @Param        to          query     string  false  "Created before"
+// @Param        pageNumber  query     int     false  "Page number, starting at 1"
+// @Param        pageSize    query     int     false  "Page size, at most 100"
+// @Success      200         {object}  helper.BaseHttpResponse{result=dto.PagedList[dto.AuditLogResponse]}  "Audit records"
+// @Failure      401         {object}  helper.BaseHttpResponse  "Invalid or expired token"
+// @Failure      403         {object}  helper.BaseHttpResponse  "Not an admin"
+// @Failure      422         {object}  helper.BaseHttpResponse  "Validation error"
+// @Failure      500         {object}  helper.BaseHttpResponse  "Internal server error"
+// @Security     BearerAuth
+// @Router       /api/v1/audit-logs [get]
+func (h *AuditLogHandler) List(c *gin.Context) {
+	req := new(dto.AuditLogFilter)
+	if err := c.ShouldBindQuery(req); err != nil {
+		helper.AbortWithJSON(
+			c,
+			http.StatusUnprocessableEntity,
+			helper.GenerateBaseResponseWithValidationError(nil, false, helper.ValidationError, err),
+		)
+		return
+	}
+	res, err := h.auditLogService.List(c.Request.Context(), req)
+	if err != nil {
+		helper.AbortWithError(c, err)
+		return
+	}
+	helper.JSON(c, http.StatusOK, helper.GenerateBaseResponse(res, true, helper.Success))
+}
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"base_structure/src/pkg/health"
+	"base_structure/src/pkg/lifecycle"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newHealthEnv(redisErr error) (*gin.Engine, *lifecycle.Registry) {
+	checks := health.NewRegistry(0)
+	checks.Register(
+		health.NewChecker("postgres", func(context.Context) error { return nil }),
+		health.NewChecker("redis", func(context.Context) error { return redisErr }),
+	)
+	hooks := lifecycle.New()
+	hooks.SetReady(true)
+	h := NewHealthHandler(checks, hooks)
+	r := gin.New()
+	r.GET("/healthz", h.Live)
+	r.GET("/readyz", h.Ready)
+	return r, hooks
+}
+
+func probe(t *testing.T, r *gin.Engine, path string) (int, health.Report) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	var report health.Report
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	return w.Code, report
+}
+
+func TestHealthProbes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("ready", func(t *testing.T) {
+		r, _ := newHealthEnv(nil)
+		code, report := probe(t, r, "/readyz")
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, health.StatusUp, report.Checks["redis"].Status)
+	})
+
+	t.Run("dependency down", func(t *testing.T) {
+		r, _ := newHealthEnv(errors.New("connection refused"))
+		code, report := probe(t, r, "/readyz")
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Equal(t, health.StatusDown, report.Status)
+		assert.Equal(t, "connection refused", report.Checks["redis"].Error)
+
+		code, _ = probe(t, r, "/healthz")
+		assert.Equal(t, http.StatusOK, code)
+	})
+
+	t.Run("shutting down", func(t *testing.T) {
+		r, hooks := newHealthEnv(nil)
+		hooks.SetReady(false)
+		code, report := probe(t, r, "/readyz")
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Equal(t, health.StatusDown, report.Checks["server"].Status)
+	})
+}
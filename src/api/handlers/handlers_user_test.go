@@ -7,6 +7,7 @@ import (
 	"base_structure/src/constants"
 	"base_structure/src/pkg/service_errors"
 	"bytes"
+	"context"
 	"encoding/json"
 	"github.com/alicebob/miniredis/v2"
 	"github.com/gin-gonic/gin"
@@ -40,18 +41,18 @@ func TestMain(m *testing.M) {
 
 type mockUserService struct{ mock.Mock }
 
-func (m *mockUserService) SendOtp(r *dto.GetOtpRequest) error {
+func (m *mockUserService) SendOtp(_ context.Context, r *dto.GetOtpRequest) error {
 	return m.Called(r).Error(0)
 }
-func (m *mockUserService) LoginByUsername(r *dto.LoginByUsernameRequest) (*dto.TokenDetail, error) {
+func (m *mockUserService) LoginByUsername(_ context.Context, r *dto.LoginByUsernameRequest) (*dto.TokenDetail, error) {
 	args := m.Called(r)
 	td, _ := args.Get(0).(*dto.TokenDetail)
 	return td, args.Error(1)
 }
-func (m *mockUserService) RegisterByUsername(r *dto.RegisterByUsernameRequest) error {
+func (m *mockUserService) RegisterByUsername(_ context.Context, r *dto.RegisterByUsernameRequest) error {
 	return m.Called(r).Error(0)
 }
-func (m *mockUserService) RegisterLoginByMobileNumber(r *dto.RegisterLoginByMobileRequest) (*dto.TokenDetail, error) {
+func (m *mockUserService) RegisterLoginByMobileNumber(_ context.Context, r *dto.RegisterLoginByMobileRequest) (*dto.TokenDetail, error) {
 	args := m.Called(r)
 	td, _ := args.Get(0).(*dto.TokenDetail)
 	return td, args.Error(1)
@@ -137,7 +138,7 @@ func TestSendOtp(t *testing.T) {
 	t.Run("otp exists", func(t *testing.T) {
 		r, ms := newSendOtpEnv()
 		ms.On("SendOtp", mock.Anything).
-			Return(&service_errors.ServiceError{EndUserMessage: service_errors.OtpExists}).Once()
+			Return(service_errors.New(service_errors.ErrOtpExists)).Once()
 
 		w := perform(r, "/api/v1/users/send-otp", dto.GetOtpRequest{MobileNumber: "09123456789"})
 		assert.Equal(t, http.StatusConflict, w.Code)
@@ -173,7 +174,7 @@ func TestLoginByUsername(t *testing.T) {
 		r, ms := newLoginEnv()
 		ms.On("LoginByUsername", mock.Anything).
 			Return((*dto.TokenDetail)(nil),
-				&service_errors.ServiceError{EndUserMessage: service_errors.InvalidCredentials}).Once()
+				service_errors.New(service_errors.ErrInvalidCredentials)).Once()
 
 		w := perform(r, "/api/v1/users/login-by-username",
 			dto.LoginByUsernameRequest{Username: "admin", Password: "badpass"})
@@ -212,7 +213,7 @@ func TestRegisterByUsername(t *testing.T) {
 	t.Run("username exists", func(t *testing.T) {
 		r, ms := newRegisterEnv()
 		ms.On("RegisterByUsername", mock.Anything).
-			Return(&service_errors.ServiceError{EndUserMessage: service_errors.UsernameExists}).Once()
+			Return(service_errors.New(service_errors.ErrUsernameExists)).Once()
 		w := perform(r, "/api/v1/users/register-by-username", valid)
 		assert.Equal(t, http.StatusConflict, w.Code)
 		ms.AssertExpectations(t)
@@ -221,7 +222,7 @@ func TestRegisterByUsername(t *testing.T) {
 	t.Run("email exists", func(t *testing.T) {
 		r, ms := newRegisterEnv()
 		ms.On("RegisterByUsername", mock.Anything).
-			Return(&service_errors.ServiceError{EndUserMessage: service_errors.EmailExists}).Once()
+			Return(service_errors.New(service_errors.ErrEmailExists)).Once()
 		w := perform(r, "/api/v1/users/register-by-username", valid)
 		assert.Equal(t, http.StatusConflict, w.Code)
 		ms.AssertExpectations(t)
@@ -256,7 +257,7 @@ func TestRegisterLoginByMobileNumber(t *testing.T) {
 		r, ms := newMobileEnv()
 		ms.On("RegisterLoginByMobileNumber", mock.Anything).
 			Return((*dto.TokenDetail)(nil),
-				&service_errors.ServiceError{EndUserMessage: service_errors.OtpNotValid}).Once()
+				service_errors.New(service_errors.ErrOtpNotValid)).Once()
 		w := perform(r, "/api/v1/users/login-by-mobile", valid)
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 		ms.AssertExpectations(t)
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"base_structure/src/pkg/health"
+	"base_structure/src/pkg/lifecycle"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type HealthHandler struct {
+	checks *health.Registry
+	hooks  *lifecycle.Registry
+}
+
+func NewHealthHandler(checks *health.Registry, hooks *lifecycle.Registry) *HealthHandler {
+	return &HealthHandler{
+		checks: checks,
+		hooks:  hooks,
+	}
+}
+
+// Live
+// @Summary      Liveness probe
+// @Description  Answers as long as the process serves HTTP. It checks no dependencies.
+// @Tags         Health
+// @Produce      json
+// @Success      200  {object}  health.Report  "Alive"
+// @Router       /healthz [get]
+func (h *HealthHandler) Live(c *gin.Context) {
+	c.JSON(http.StatusOK, &health.Report{Status: health.StatusUp, Checks: map[string]health.CheckResult{}})
+}
+
+// Ready
+// @Summary      Readiness probe
+// @Description  Checks Postgres, Redis, pending migrations and every other registered dependency. It is down while
+// @Description  the server shuts down.
+// @Tags         Health
+// @Produce      json
+// @Success      200  {object}  health.Report  "Ready"
+// @Failure      503  {object}  health.Report  "A dependency is down or the server is shutting down"
+// @Router       /readyz [get]
+func (h *HealthHandler) Ready(c *gin.Context) {
+	if !h.hooks.Ready() {
+		c.JSON(http.StatusServiceUnavailable, &health.Report{
+			Status: health.StatusDown,
+			Checks: map[string]health.CheckResult{
+				"server": {Status: health.StatusDown, Error: "shutting down"},
+			},
+		})
+		return
+	}
+	report := h.checks.Run(c.Request.Context())
+	if !report.Up() {
+		c.JSON(http.StatusServiceUnavailable, report)
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"base_structure/src/api/dto"
+	"base_structure/src/api/helper"
+	"base_structure/src/config"
+	"base_structure/src/pkg/logging"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type LogLevelHandler struct {
+	cfg    *config.Config
+	logger logging.Logger
+}
+
+func NewLogLevelHandler(cfg *config.Config) *LogLevelHandler {
+	return &LogLevelHandler{
+		cfg:    cfg,
+		logger: logging.NewLogger(cfg),
+	}
+}
+
+// Get
+// @Summary      Current log levels
+// @Description  Returns the global log level and the per category overrides.
+// @Tags         Debug
+// @Produce      json
+// @Success      200  {object}  helper.BaseHttpResponse{result=dto.LogLevelResponse}  "Log levels"
+// @Failure      401  {object}  helper.BaseHttpResponse  "Invalid or expired token"
+// @Failure      403  {object}  helper.BaseHttpResponse  "Not an admin"
+// @Security     BearerAuth
+// @Router       /api/v1/debug/log-level [get]
+func (h *LogLevelHandler) Get(c *gin.Context) {
+	helper.JSON(c, http.StatusOK, helper.GenerateBaseResponse(toLogLevelResponse(logging.Levels()), true, helper.Success))
+}
+
+// Update
+// @Summary      Change log levels
+// @Description  Changes the global log level and per category overrides until the next restart or SIGHUP. An empty category level removes its override.
+// @Tags         Debug
+// @Accept       json
+// @Produce      json
+// @Param        payload  body      dto.UpdateLogLevelRequest  true  "Levels to change"
+// @Success      200      {object}  helper.BaseHttpResponse{result=dto.LogLevelResponse}  "Log levels in effect"
+// @Failure      401      {object}  helper.BaseHttpResponse  "Invalid or expired token"
+// @Failure      403      {object}  helper.BaseHttpResponse  "Not an admin"
+// @Failure      422      {object}  helper.BaseHttpResponse  "Unknown level or category"
+// @Security     BearerAuth
+// @Router       /api/v1/debug/log-level [put]
+func (h *LogLevelHandler) Update(c *gin.Context) {
+	req := new(dto.UpdateLogLevelRequest)
+	if err := c.ShouldBindJSON(req); err != nil {
+		helper.AbortWithJSON(
+			c,
+			http.StatusUnprocessableEntity,
+			helper.GenerateBaseResponseWithValidationError(nil, false, helper.ValidationError, err),
+		)
+		return
+	}
+	if err := logging.UpdateLevels(req.Level, req.Categories); err != nil {
+		helper.AbortWithJSON(
+			c,
+			http.StatusUnprocessableEntity,
+			helper.GenerateBaseResponseWithError(nil, false, helper.ValidationError, err),
+		)
+		return
+	}
+	levels := logging.Levels()
+	h.logger.WithContext(c.Request.Context()).Warn(logging.Internal, logging.LogLevel, "log levels changed", map[logging.ExtraKey]interface{}{
+		logging.GlobalLevel:    levels.Level,
+		logging.CategoryLevels: levels.Categories,
+	})
+	helper.JSON(c, http.StatusOK, helper.GenerateBaseResponse(toLogLevelResponse(levels), true, helper.Success))
+}
+
+func toLogLevelResponse(levels logging.LevelsSnapshot) *dto.LogLevelResponse {
+	res := &dto.LogLevelResponse{Level: levels.Level, Categories: make(map[string]string, len(levels.Categories))}
+	for cat, lvl := range levels.Categories {
+		res.Categories[string(cat)] = lvl
+	}
+	return res
+}
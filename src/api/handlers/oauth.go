@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"base_structure/src/api/dto"
+	"base_structure/src/api/helper"
+	"base_structure/src/config"
+	"base_structure/src/services"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type OAuthHandler struct {
+	cfg                 *config.Config
+	externalAuthService services.ExternalAuthServiceIface
+}
+
+func NewOAuthHandler(cfg *config.Config) *OAuthHandler {
+	return &OAuthHandler{
+		cfg:                 cfg,
+		externalAuthService: services.NewExternalAuthService(cfg),
+	}
+}
+
+func NewOAuthHandlerWithSvc(cfg *config.Config, svc services.ExternalAuthServiceIface) *OAuthHandler {
+	return &OAuthHandler{
+		cfg:                 cfg,
+		externalAuthService: svc,
+	}
+}
+
+// Login
+// @Summary      Start social login
+// @Description  Returns the authorization URL of the external identity provider. The state and nonce are kept in Redis until the callback.
+// @Tags         OAuth
+// @Produce      json
+// @Param        provider  path      string  true  "Provider name"
+// @Success      200       {object}  helper.BaseHttpResponse{result=dto.OAuthAuthUrlResponse}  "Authorization URL"
+// @Failure      404       {object}  helper.BaseHttpResponse                                     "Provider not found"
+// @Failure      500       {object}  helper.BaseHttpResponse                                     "Internal server error"
+// @Router       /api/v1/users/oauth/{provider}/login [get]
+func (h *OAuthHandler) Login(c *gin.Context) {
+	res, err := h.externalAuthService.AuthUrl(c.Request.Context(), c.Param("provider"), 0)
+	if err != nil {
+		helper.AbortWithError(c, err)
+		return
+	}
+	helper.JSON(c, http.StatusOK, helper.GenerateBaseResponse(res, true, helper.Success))
+}
+
+// Callback
+// @Summary      Finish social login
+// @Description  Exchanges the authorization code, then logs in or auto-provisions the user and returns tokens. States of a link flow are rejected.
+// @Tags         OAuth
+// @Produce      json
+// @Param        provider  path      string  true  "Provider name"
+// @Param        code      query     string  true  "Authorization code"
+// @Param        state     query     string  true  "State returned by the provider"
+// @Success      201       {object}  helper.BaseHttpResponse{result=dto.TokenDetail}  "Tokens returned"
+// @Failure      400       {object}  helper.BaseHttpResponse                            "Invalid or expired state"
+// @Failure      401       {object}  helper.BaseHttpResponse                            "Code exchange failed"
+// @Failure      409       {object}  helper.BaseHttpResponse                            "Identity linked to another user"
+// @Failure      422       {object}  helper.BaseHttpResponse                            "Validation error"
+// @Failure      500       {object}  helper.BaseHttpResponse                            "Internal server error"
+// @Router       /api/v1/users/oauth/{provider}/callback [get]
+func (h *OAuthHandler) Callback(c *gin.Context) {
+	req := new(dto.OAuthCallbackRequest)
+	if err := c.ShouldBindQuery(req); err != nil {
+		helper.AbortWithJSON(
+			c,
+			http.StatusUnprocessableEntity,
+			helper.GenerateBaseResponseWithValidationError(nil, false, helper.ValidationError, err),
+		)
+		return
+	}
+	token, err := h.externalAuthService.Callback(c.Request.Context(), c.Param("provider"), req)
+	if err != nil {
+		helper.AbortWithError(c, err)
+		return
+	}
+	helper.JSON(c, http.StatusCreated, helper.GenerateBaseResponse(token, true, helper.Success))
+}
+
+// Link
+// @Summary      Link external identity
+// @Description  Returns the authorization URL of the provider; the link callback, called by the same user, links the identity.
+// @Tags         OAuth
+// @Produce      json
+// @Param        provider  path      string  true  "Provider name"
+// @Success      200       {object}  helper.BaseHttpResponse{result=dto.OAuthAuthUrlResponse}  "Authorization URL"
+// @Failure      401       {object}  helper.BaseHttpResponse                                     "Invalid or expired token"
+// @Failure      404       {object}  helper.BaseHttpResponse                                     "Provider not found"
+// @Failure      500       {object}  helper.BaseHttpResponse                                     "Internal server error"
+// @Security     BearerAuth
+// @Router       /api/v1/users/oauth/{provider}/link [post]
+func (h *OAuthHandler) Link(c *gin.Context) {
+	userId, err := helper.GetUserId(c)
+	if err != nil {
+		helper.AbortWithError(c, err)
+		return
+	}
+	res, err := h.externalAuthService.AuthUrl(c.Request.Context(), c.Param("provider"), userId)
+	if err != nil {
+		helper.AbortWithError(c, err)
+		return
+	}
+	helper.JSON(c, http.StatusOK, helper.GenerateBaseResponse(res, true, helper.Success))
+}
+
+// LinkCallback
+// @Summary      Finish linking external identity
+// @Description  Exchanges the authorization code of a link flow started by the authenticated user and links the identity to them.
+// @Tags         OAuth
+// @Produce      json
+// @Param        provider  path      string  true  "Provider name"
+// @Param        code      query     string  true  "Authorization code"
+// @Param        state     query     string  true  "State returned by the provider"
+// @Success      200       {object}  helper.BaseHttpResponse  "Identity linked"
+// @Failure      400       {object}  helper.BaseHttpResponse  "Invalid or expired state"
+// @Failure      401       {object}  helper.BaseHttpResponse  "Invalid token or code exchange failed"
+// @Failure      409       {object}  helper.BaseHttpResponse  "Identity linked to another user"
+// @Failure      422       {object}  helper.BaseHttpResponse  "Validation error"
+// @Failure      500       {object}  helper.BaseHttpResponse  "Internal server error"
+// @Security     BearerAuth
+// @Router       /api/v1/users/oauth/{provider}/link/callback [get]
+func (h *OAuthHandler) LinkCallback(c *gin.Context) {
+	req := new(dto.OAuthCallbackRequest)
+	if err := c.ShouldBindQuery(req); err != nil {
+		helper.AbortWithJSON(
+			c,
+			http.StatusUnprocessableEntity,
+			helper.GenerateBaseResponseWithValidationError(nil, false, helper.ValidationError, err),
+		)
+		return
+	}
+	userId, err := helper.GetUserId(c)
+	if err != nil {
+		helper.AbortWithError(c, err)
+		return
+	}
+	err = h.externalAuthService.LinkCallback(c.Request.Context(), userId, c.Param("provider"), req)
+	if err != nil {
+		helper.AbortWithError(c, err)
+		return
+	}
+	helper.JSON(c, http.StatusOK, helper.GenerateBaseResponse("identity linked", true, helper.Success))
+}
+
+// Unlink
+// @Summary      Unlink external identity
+// @Description  Removes the link between the authenticated user and the provider.
+// @Tags         OAuth
+// @Produce      json
+// @Param        provider  path      string  true  "Provider name"
+// @Success      200       {object}  helper.BaseHttpResponse  "Identity unlinked"
+// @Failure      401       {object}  helper.BaseHttpResponse  "Invalid or expired token"
+// @Failure      404       {object}  helper.BaseHttpResponse  "Identity not linked"
+// @Failure      409       {object}  helper.BaseHttpResponse  "Last login method"
+// @Failure      500       {object}  helper.BaseHttpResponse  "Internal server error"
+// @Security     BearerAuth
+// @Router       /api/v1/users/oauth/{provider} [delete]
+func (h *OAuthHandler) Unlink(c *gin.Context) {
+	userId, err := helper.GetUserId(c)
+	if err != nil {
+		helper.AbortWithError(c, err)
+		return
+	}
+	err = h.externalAuthService.Unlink(c.Request.Context(), userId, c.Param("provider"))
+	if err != nil {
+		helper.AbortWithError(c, err)
+		return
+	}
+	helper.JSON(c, http.StatusOK, helper.GenerateBaseResponse("identity unlinked", true, helper.Success))
+}
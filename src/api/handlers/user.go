@@ -5,11 +5,13 @@ import (
 	"base_structure/src/api/helper"
 	"base_structure/src/config"
 	"base_structure/src/constants"
+	"base_structure/src/pkg/audit"
 	"base_structure/src/pkg/service_errors"
 	"base_structure/src/services"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt"
 	"net/http"
+	"strconv"
 	"time"
 )
 
@@ -48,22 +50,20 @@ func (h *UserHandler) SendOtp(c *gin.Context) {
 	req := new(dto.GetOtpRequest)
 	err := c.ShouldBindJSON(&req)
 	if err != nil {
-		c.AbortWithStatusJSON(
+		helper.AbortWithJSON(
+			c,
 			http.StatusUnprocessableEntity,
 			helper.GenerateBaseResponseWithValidationError(nil, false, helper.ValidationError, err),
 		)
 		return
 	}
-	err = h.userService.SendOtp(req)
+	err = h.userService.SendOtp(c.Request.Context(), req)
 	if err != nil {
-		c.AbortWithStatusJSON(
-			helper.TranslateErrorToStatusCode(err),
-			helper.GenerateBaseResponseWithError(nil, false, helper.InternalError, err),
-		)
+		helper.AbortWithError(c, err)
 		return
 	}
 	// Call internal sms service
-	c.JSON(http.StatusCreated, helper.GenerateBaseResponse("otp sent", true, helper.Success))
+	helper.JSON(c, http.StatusCreated, helper.GenerateBaseResponse("otp sent", true, helper.Success))
 }
 
 // LoginByUsername
@@ -82,21 +82,19 @@ func (h *UserHandler) LoginByUsername(c *gin.Context) {
 	req := new(dto.LoginByUsernameRequest)
 	err := c.ShouldBindJSON(&req)
 	if err != nil {
-		c.AbortWithStatusJSON(
+		helper.AbortWithJSON(
+			c,
 			http.StatusUnprocessableEntity,
 			helper.GenerateBaseResponseWithValidationError(nil, false, helper.ValidationError, err),
 		)
 		return
 	}
-	token, err := h.userService.LoginByUsername(req)
+	token, err := h.userService.LoginByUsername(c.Request.Context(), req)
 	if err != nil {
-		c.AbortWithStatusJSON(
-			helper.TranslateErrorToStatusCode(err),
-			helper.GenerateBaseResponseWithError(nil, false, helper.InternalError, err),
-		)
+		helper.AbortWithError(c, err)
 		return
 	}
-	c.JSON(http.StatusCreated, helper.GenerateBaseResponse(token, true, helper.Success))
+	helper.JSON(c, http.StatusCreated, helper.GenerateBaseResponse(token, true, helper.Success))
 }
 
 // RegisterByUsername
@@ -115,21 +113,19 @@ func (h *UserHandler) RegisterByUsername(c *gin.Context) {
 	req := new(dto.RegisterByUsernameRequest)
 	err := c.ShouldBindJSON(&req)
 	if err != nil {
-		c.AbortWithStatusJSON(
+		helper.AbortWithJSON(
+			c,
 			http.StatusUnprocessableEntity,
 			helper.GenerateBaseResponseWithValidationError(nil, false, helper.ValidationError, err),
 		)
 		return
 	}
-	err = h.userService.RegisterByUsername(req)
+	err = h.userService.RegisterByUsername(c.Request.Context(), req)
 	if err != nil {
-		c.AbortWithStatusJSON(
-			helper.TranslateErrorToStatusCode(err),
-			helper.GenerateBaseResponseWithError(nil, false, helper.InternalError, err),
-		)
+		helper.AbortWithError(c, err)
 		return
 	}
-	c.JSON(http.StatusCreated, helper.GenerateBaseResponse(nil, true, helper.Success))
+	helper.JSON(c, http.StatusCreated, helper.GenerateBaseResponse(nil, true, helper.Success))
 }
 
 // RegisterLoginByMobileNumber
@@ -151,21 +147,19 @@ func (h *UserHandler) RegisterLoginByMobileNumber(c *gin.Context) {
 	req := new(dto.RegisterLoginByMobileRequest)
 	err := c.ShouldBindJSON(&req)
 	if err != nil {
-		c.AbortWithStatusJSON(
+		helper.AbortWithJSON(
+			c,
 			http.StatusUnprocessableEntity,
 			helper.GenerateBaseResponseWithValidationError(nil, false, helper.ValidationError, err),
 		)
 		return
 	}
-	token, err := h.userService.RegisterLoginByMobileNumber(req)
+	token, err := h.userService.RegisterLoginByMobileNumber(c.Request.Context(), req)
 	if err != nil {
-		c.AbortWithStatusJSON(
-			helper.TranslateErrorToStatusCode(err),
-			helper.GenerateBaseResponseWithError(nil, false, helper.InternalError, err),
-		)
+		helper.AbortWithError(c, err)
 		return
 	}
-	c.JSON(http.StatusCreated, helper.GenerateBaseResponse(token, true, helper.Success))
+	helper.JSON(c, http.StatusCreated, helper.GenerateBaseResponse(token, true, helper.Success))
 }
 
 // Logout
@@ -184,7 +178,8 @@ func (h *UserHandler) RegisterLoginByMobileNumber(c *gin.Context) {
 func (h *UserHandler) Logout(c *gin.Context) {
 	req := new(dto.LogoutRequest)
 	if err := c.ShouldBindJSON(req); err != nil {
-		c.AbortWithStatusJSON(
+		helper.AbortWithJSON(
+			c,
 			http.StatusUnprocessableEntity,
 			helper.GenerateBaseResponseWithValidationError(nil, false, helper.ValidationError, err),
 		)
@@ -193,26 +188,19 @@ func (h *UserHandler) Logout(c *gin.Context) {
 	auth := c.GetHeader(constants.AuthorizationHeaderKey)
 	accessToken, err := helper.ExtractToken(auth)
 	if err != nil {
-		c.AbortWithStatusJSON(
-			http.StatusUnauthorized,
-			helper.GenerateBaseResponseWithError(nil, false, helper.AuthError,
-				&service_errors.ServiceError{EndUserMessage: service_errors.TokenInvalid}),
-		)
+		helper.AbortWithError(c, service_errors.Wrap(service_errors.ErrTokenInvalid, err))
 		return
 	}
 	tokenSvc := services.NewTokenService(h.cfg)
 	blackSvc := services.NewBlacklistService(h.cfg)
 	acClaims, err := tokenSvc.GetClaims(accessToken)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusUnauthorized,
-			helper.GenerateBaseResponseWithError(nil, false, helper.AuthError, err))
+		helper.AbortWithError(c, service_errors.Wrap(service_errors.ErrTokenInvalid, err))
 		return
 	}
 	rtParsed, err := tokenSvc.VerifyRefreshToken(req.RefreshToken)
 	if err != nil || !rtParsed.Valid {
-		c.AbortWithStatusJSON(http.StatusUnauthorized,
-			helper.GenerateBaseResponseWithError(nil, false, helper.AuthError,
-				&service_errors.ServiceError{EndUserMessage: service_errors.TokenInvalid}))
+		helper.AbortWithError(c, service_errors.New(service_errors.ErrTokenInvalid))
 		return
 	}
 	rtClaims := rtParsed.Claims.(jwt.MapClaims)
@@ -225,15 +213,19 @@ func (h *UserHandler) Logout(c *gin.Context) {
 		}
 		return 0
 	}
-	if err := blackSvc.Blacklist(accessToken, ttl(acClaims)); err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError,
-			helper.GenerateBaseResponseWithError(nil, false, helper.InternalError, err))
+	if err := blackSvc.Blacklist(c.Request.Context(), accessToken, ttl(acClaims)); err != nil {
+		helper.AbortWithError(c, err)
 		return
 	}
-	if err := blackSvc.Blacklist(req.RefreshToken, ttl(rtClaims)); err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError,
-			helper.GenerateBaseResponseWithError(nil, false, helper.InternalError, err))
+	if err := blackSvc.Blacklist(c.Request.Context(), req.RefreshToken, ttl(rtClaims)); err != nil {
+		helper.AbortWithError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, helper.GenerateBaseResponse("logged out", true, helper.Success))
+	userId, _ := helper.GetUserId(c)
+	services.GetAuditLogger(h.cfg).Log(c.Request.Context(), services.AuditEvent{
+		Action:     audit.Logout,
+		TargetType: audit.TargetUser,
+		TargetId:   strconv.FormatUint(uint64(userId), 10),
+	})
+	helper.JSON(c, http.StatusOK, helper.GenerateBaseResponse("logged out", true, helper.Success))
 }
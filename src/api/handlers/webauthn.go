@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"base_structure/src/api/dto"
+	"base_structure/src/api/helper"
+	"base_structure/src/config"
+	"base_structure/src/services"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type WebAuthnHandler struct {
+	cfg             *config.Config
+	webAuthnService services.WebAuthnServiceIface
+}
+
+func NewWebAuthnHandler(cfg *config.Config) *WebAuthnHandler {
+	return &WebAuthnHandler{
+		cfg:             cfg,
+		webAuthnService: services.NewWebAuthnService(cfg),
+	}
+}
+
+func NewWebAuthnHandlerWithSvc(cfg *config.Config, svc services.WebAuthnServiceIface) *WebAuthnHandler {
+	return &WebAuthnHandler{
+		cfg:             cfg,
+		webAuthnService: svc,
+	}
+}
+
+// BeginRegistration
+// @Summary      Begin passkey registration
+// @Description  Starts a WebAuthn registration ceremony for the authenticated user and returns the credential creation options.
+// @Tags         WebAuthn
+// @Produce      json
+// @Success      201      {object}  helper.BaseHttpResponse  "Credential creation options"
+// @Failure      401      {object}  helper.BaseHttpResponse  "Invalid or expired token"
+// @Failure      500      {object}  helper.BaseHttpResponse  "Internal server error"
+// @Security     BearerAuth
+// @Router       /api/v1/users/webauthn/register/begin [post]
+func (h *WebAuthnHandler) BeginRegistration(c *gin.Context) {
+	userId, err := helper.GetUserId(c)
+	if err != nil {
+		helper.AbortWithError(c, err)
+		return
+	}
+	creation, err := h.webAuthnService.BeginRegistration(c.Request.Context(), userId)
+	if err != nil {
+		helper.AbortWithError(c, err)
+		return
+	}
+	helper.JSON(c, http.StatusCreated, helper.GenerateBaseResponse(creation, true, helper.Success))
+}
+
+// FinishRegistration
+// @Summary      Finish passkey registration
+// @Description  Verifies the authenticator attestation and stores the new credential for the authenticated user.
+// @Tags         WebAuthn
+// @Accept       json
+// @Produce      json
+// @Param        payload  body      dto.WebAuthnFinishRegistrationRequest  true  "Attestation response"
+// @Success      201      {object}  helper.BaseHttpResponse  "Credential registered"
+// @Failure      400      {object}  helper.BaseHttpResponse  "Challenge not found or expired"
+// @Failure      401      {object}  helper.BaseHttpResponse  "Verification failed"
+// @Failure      422      {object}  helper.BaseHttpResponse  "Validation error"
+// @Failure      500      {object}  helper.BaseHttpResponse  "Internal server error"
+// @Security     BearerAuth
+// @Router       /api/v1/users/webauthn/register/finish [post]
+func (h *WebAuthnHandler) FinishRegistration(c *gin.Context) {
+	req := new(dto.WebAuthnFinishRegistrationRequest)
+	if err := c.ShouldBindJSON(req); err != nil {
+		helper.AbortWithJSON(
+			c,
+			http.StatusUnprocessableEntity,
+			helper.GenerateBaseResponseWithValidationError(nil, false, helper.ValidationError, err),
+		)
+		return
+	}
+	userId, err := helper.GetUserId(c)
+	if err != nil {
+		helper.AbortWithError(c, err)
+		return
+	}
+	err = h.webAuthnService.FinishRegistration(c.Request.Context(), userId, req)
+	if err != nil {
+		helper.AbortWithError(c, err)
+		return
+	}
+	helper.JSON(c, http.StatusCreated, helper.GenerateBaseResponse(nil, true, helper.Success))
+}
+
+// BeginLogin
+// @Summary      Begin passkey login
+// @Description  Starts a WebAuthn assertion ceremony for the given username and returns the credential request options with a ceremony id to send back on finish. Unknown usernames and users without passkeys get a discoverable challenge.
+// @Tags         WebAuthn
+// @Accept       json
+// @Produce      json
+// @Param        payload  body      dto.WebAuthnBeginLoginRequest  true  "Username"
+// @Success      201      {object}  helper.BaseHttpResponse{result=dto.WebAuthnBeginLoginResponse}  "Ceremony id and credential request options"
+// @Failure      422      {object}  helper.BaseHttpResponse                                         "Validation error"
+// @Failure      500      {object}  helper.BaseHttpResponse                                         "Internal server error"
+// @Router       /api/v1/users/webauthn/login/begin [post]
+func (h *WebAuthnHandler) BeginLogin(c *gin.Context) {
+	req := new(dto.WebAuthnBeginLoginRequest)
+	if err := c.ShouldBindJSON(req); err != nil {
+		helper.AbortWithJSON(
+			c,
+			http.StatusUnprocessableEntity,
+			helper.GenerateBaseResponseWithValidationError(nil, false, helper.ValidationError, err),
+		)
+		return
+	}
+	res, err := h.webAuthnService.BeginLogin(c.Request.Context(), req)
+	if err != nil {
+		helper.AbortWithError(c, err)
+		return
+	}
+	helper.JSON(c, http.StatusCreated, helper.GenerateBaseResponse(res, true, helper.Success))
+}
+
+// FinishLogin
+// @Summary      Finish passkey login
+// @Description  Verifies the authenticator assertion and returns a JWT access/refresh pair.
+// @Tags         WebAuthn
+// @Accept       json
+// @Produce      json
+// @Param        payload  body      dto.WebAuthnFinishLoginRequest  true  "Assertion response"
+// @Success      201      {object}  helper.BaseHttpResponse{result=dto.TokenDetail}  "Tokens returned"
+// @Failure      400      {object}  helper.BaseHttpResponse                            "Challenge not found or expired"
+// @Failure      401      {object}  helper.BaseHttpResponse                            "Verification failed"
+// @Failure      422      {object}  helper.BaseHttpResponse                            "Validation error"
+// @Failure      500      {object}  helper.BaseHttpResponse                            "Internal server error"
+// @Router       /api/v1/users/webauthn/login/finish [post]
+func (h *WebAuthnHandler) FinishLogin(c *gin.Context) {
+	req := new(dto.WebAuthnFinishLoginRequest)
+	if err := c.ShouldBindJSON(req); err != nil {
+		helper.AbortWithJSON(
+			c,
+			http.StatusUnprocessableEntity,
+			helper.GenerateBaseResponseWithValidationError(nil, false, helper.ValidationError, err),
+		)
+		return
+	}
+	token, err := h.webAuthnService.FinishLogin(c.Request.Context(), req)
+	if err != nil {
+		helper.AbortWithError(c, err)
+		return
+	}
+	helper.JSON(c, http.StatusCreated, helper.GenerateBaseResponse(token, true, helper.Success))
+}
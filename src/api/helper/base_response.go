@@ -8,6 +8,7 @@ type BaseHttpResponse struct {
 	ResultCode       ResultCode                     `json:"resultCode"`
 	ValidationErrors *[]validations.ValidationError `json:"validationErrors"`
 	Error            any                            `json:"error"`
+	RequestId        string                         `json:"requestId,omitempty"`
 }
 
 func GenerateBaseResponse(result any, success bool, resultCode ResultCode) *BaseHttpResponse {
@@ -0,0 +1,21 @@
+package helper
+
+import (
+	"base_structure/src/constants"
+	"base_structure/src/pkg/service_errors"
+	"errors"
+	"github.com/gin-gonic/gin"
+)
+
+// GetUserId returns the id of the authenticated user set by the Authentication middleware.
+func GetUserId(c *gin.Context) (uint, error) {
+	value, ok := c.Get(constants.UserIdKey)
+	if !ok {
+		return 0, service_errors.Wrap(service_errors.ErrClaimsNotFound, errors.New("user id not found in context"))
+	}
+	id, ok := value.(float64)
+	if !ok || id <= 0 {
+		return 0, service_errors.Wrap(service_errors.ErrTokenInvalid, errors.New("invalid user id in context"))
+	}
+	return uint(id), nil
+}
@@ -0,0 +1,19 @@
+package helper
+
+import "github.com/gin-gonic/gin"
+
+const clientIpKey = "ClientIp"
+
+// SetClientIP stores the client address resolved by the ClientIP middleware.
+func SetClientIP(c *gin.Context, ip string) {
+	c.Set(clientIpKey, ip)
+}
+
+// ClientIP returns the address of the client behind the trusted proxies. Limiters, logs and audit records all use it,
+// so they agree on who sent a request. Outside the ClientIP middleware it falls back to gin's own resolution.
+func ClientIP(c *gin.Context) string {
+	if ip := c.GetString(clientIpKey); ip != "" {
+		return ip
+	}
+	return c.ClientIP()
+}
@@ -0,0 +1,109 @@
+package helper
+
+import (
+	"base_structure/src/api/validations"
+	"base_structure/src/pkg/i18n"
+	"base_structure/src/pkg/service_errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strings"
+)
+
+const (
+	EnvelopeMode  = "envelope"
+	ProblemMode   = "problem"
+	NegotiateMode = "negotiate"
+
+	ProblemContentType = "application/problem+json"
+
+	problemKey    = "ProblemDetails"
+	resultCodeKey = "ResultCode"
+)
+
+// ProblemDetails is an error response as described by RFC 9457, with the resultCode, validationErrors and requestId of
+// BaseHttpResponse as extension members.
+type ProblemDetails struct {
+	Type             string                         `json:"type"`
+	Title            string                         `json:"title"`
+	Status           int                            `json:"status"`
+	Detail           string                         `json:"detail,omitempty"`
+	Instance         string                         `json:"instance,omitempty"`
+	ResultCode       ResultCode                     `json:"resultCode"`
+	ValidationErrors *[]validations.ValidationError `json:"validationErrors,omitempty"`
+	RequestId        string                         `json:"requestId,omitempty"`
+}
+
+// SetProblemDetails stores whether the errors of the request are rendered as problem details, and the prefix of their
+// type URIs. It is set by the ResponseMode middleware.
+func SetProblemDetails(c *gin.Context, typeBase string) {
+	c.Set(problemKey, typeBase)
+}
+
+func problemDetails(c *gin.Context) (string, bool) {
+	v, ok := c.Get(problemKey)
+	if !ok {
+		return "", false
+	}
+	typeBase, _ := v.(string)
+	return typeBase, true
+}
+
+// RenderedResultCode returns the result code of the response written through JSON or AbortWithJSON, if any.
+func RenderedResultCode(c *gin.Context) (ResultCode, bool) {
+	v, ok := c.Get(resultCodeKey)
+	if !ok {
+		return 0, false
+	}
+	code, ok := v.(ResultCode)
+	return code, ok
+}
+
+// WantsProblemDetails reports whether the client prefers application/problem+json over application/json.
+func WantsProblemDetails(c *gin.Context) bool {
+	return c.NegotiateFormat(gin.MIMEJSON, ProblemContentType) == ProblemContentType
+}
+
+// NewProblemDetails converts an error response. Catalogue errors get the type typeBase followed by their key and the
+// kind message, in the language of the request, as title; other errors are about:blank with the status text as title.
+func NewProblemDetails(c *gin.Context, status int, res *BaseHttpResponse, typeBase string) *ProblemDetails {
+	p := &ProblemDetails{
+		Type:             "about:blank",
+		Title:            http.StatusText(status),
+		Status:           status,
+		Instance:         c.Request.URL.Path,
+		ResultCode:       res.ResultCode,
+		ValidationErrors: res.ValidationErrors,
+		RequestId:        res.RequestId,
+	}
+	if k, ok := service_errors.KindByCode(int(res.ResultCode)); ok && typeBase != "" {
+		p.Type = typeBase + strings.TrimPrefix(k.Key, "error.")
+		p.Title = i18n.T(i18n.FromContext(c.Request.Context()), k.Key, nil)
+	}
+	switch e := res.Error.(type) {
+	case nil:
+	case string:
+		p.Detail = e
+	default:
+		p.Detail = fmt.Sprint(e)
+	}
+	return p
+}
+
+// render writes res, as problem details when it is an error and the request asked for them. Validation messages are
+// written in the language of the request.
+func render(c *gin.Context, status int, res *BaseHttpResponse, abort bool) {
+	c.Set(resultCodeKey, res.ResultCode)
+	validations.Localize(res.ValidationErrors, i18n.FromContext(c.Request.Context()))
+	var body any = res
+	if typeBase, ok := problemDetails(c); ok && !res.Success {
+		body = NewProblemDetails(c, status, res, typeBase)
+		// gin keeps a Content-Type that is already set.
+		c.Header("Content-Type", ProblemContentType)
+	}
+	if abort {
+		c.AbortWithStatusJSON(status, body)
+		return
+	}
+	c.JSON(status, body)
+}
@@ -0,0 +1,30 @@
+package helper
+
+import (
+	"base_structure/src/pkg/i18n"
+	"base_structure/src/pkg/requestid"
+	"base_structure/src/pkg/service_errors"
+	"github.com/gin-gonic/gin"
+)
+
+// JSON writes res with the given status, stamped with the id of the request. Errors are rendered as problem details
+// under the ResponseMode middleware.
+func JSON(c *gin.Context, status int, res *BaseHttpResponse) {
+	res.RequestId = requestid.FromContext(c.Request.Context())
+	render(c, status, res, false)
+}
+
+// AbortWithError responds with the status, result code and end user message of the catalogue kind of err, in the
+// language of the request. The error itself is attached to the context, so the request log keeps the cause hidden from
+// the client.
+func AbortWithError(c *gin.Context, err error) {
+	_ = c.Error(err)
+	se := service_errors.From(err).Localize(i18n.FromContext(c.Request.Context()))
+	AbortWithJSON(c, se.Kind.Status, GenerateBaseResponseWithError(nil, false, ResultCode(se.Kind.Code), se))
+}
+
+// AbortWithJSON is JSON for handlers and middlewares that stop the chain.
+func AbortWithJSON(c *gin.Context, status int, res *BaseHttpResponse) {
+	res.RequestId = requestid.FromContext(c.Request.Context())
+	render(c, status, res, true)
+}
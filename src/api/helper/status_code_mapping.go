@@ -2,29 +2,9 @@ package helper
 
 import (
 	"base_structure/src/pkg/service_errors"
-	"net/http"
 )
 
-var StatusCodeMapping = map[string]int{
-	//Otp
-	service_errors.OtpExists:   http.StatusConflict,
-	service_errors.OtpUsed:     http.StatusConflict,
-	service_errors.OtpNotValid: http.StatusBadRequest,
-
-	//User
-	service_errors.EmailExists:        http.StatusConflict,
-	service_errors.UsernameExists:     http.StatusConflict,
-	service_errors.PermissionDenied:   http.StatusForbidden,
-	service_errors.InvalidCredentials: http.StatusUnauthorized,
-
-	//DB
-	service_errors.RecordNotFound: http.StatusNotFound,
-}
-
+// TranslateErrorToStatusCode returns the HTTP status of the catalogue kind of err.
 func TranslateErrorToStatusCode(err error) int {
-	value, ok := StatusCodeMapping[err.Error()]
-	if !ok {
-		return http.StatusInternalServerError
-	}
-	return value
+	return service_errors.KindOf(err).Status
 }
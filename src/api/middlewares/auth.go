@@ -4,69 +4,86 @@ import (
 	"base_structure/src/api/helper"
 	"base_structure/src/config"
 	"base_structure/src/constants"
+	"base_structure/src/pkg/actor"
+	"base_structure/src/pkg/audit"
 	"base_structure/src/pkg/service_errors"
 	"base_structure/src/services"
 	"errors"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt"
-	"net/http"
 )
 
 func Authentication(cfg *config.Config) gin.HandlerFunc {
-	tokenSvc := services.NewTokenService(cfg)
-	blackSvc := services.NewBlacklistService(cfg)
+	return authenticate(services.NewTokenService(cfg), services.NewBlacklistService(cfg), services.GetAuditLogger(cfg))
+}
+
+// authenticate is Authentication with its services given, so tests can record the audit events.
+func authenticate(
+	tokenSvc *services.TokenService,
+	blackSvc *services.BlacklistService,
+	auditLogger services.AuditLoggerIface,
+) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		auth := c.GetHeader(constants.AuthorizationHeaderKey)
 		if auth == "" {
-			abortAuth(c, &service_errors.ServiceError{EndUserMessage: service_errors.TokenRequired})
+			helper.AbortWithError(c, service_errors.New(service_errors.ErrTokenRequired))
 			return
 		}
 		rawToken, err := helper.ExtractToken(auth)
 		if err != nil {
-			abortAuth(c, &service_errors.ServiceError{EndUserMessage: service_errors.TokenInvalid})
+			auditRejection(c, auditLogger, "malformed authorization header")
+			helper.AbortWithError(c, service_errors.New(service_errors.ErrTokenInvalid))
 			return
 		}
 		claims, err := tokenSvc.GetClaims(rawToken)
 		if err != nil {
 			var ve *jwt.ValidationError
 			if errors.As(err, &ve) && ve.Errors == jwt.ValidationErrorExpired {
-				abortAuth(c, &service_errors.ServiceError{EndUserMessage: service_errors.TokenExpired})
+				auditRejection(c, auditLogger, "expired token")
+				helper.AbortWithError(c, service_errors.Wrap(service_errors.ErrTokenExpired, err))
+				return
 			}
+			auditRejection(c, auditLogger, "invalid token")
+			helper.AbortWithError(c, service_errors.Wrap(service_errors.ErrTokenInvalid, err))
 			return
 		}
-		if black, _ := blackSvc.IsBlacklisted(rawToken); black {
-			abortAuth(c, &service_errors.ServiceError{EndUserMessage: service_errors.TokenInvalid})
+		if black, _ := blackSvc.IsBlacklisted(c.Request.Context(), rawToken); black {
+			auditRejection(c, auditLogger, "revoked token")
+			helper.AbortWithError(c, service_errors.New(service_errors.ErrTokenInvalid))
 			return
 		}
 		for k, v := range claims {
 			c.Set(k, v)
 		}
+		userId, err := helper.GetUserId(c)
+		if err != nil {
+			auditRejection(c, auditLogger, "token without user id")
+			helper.AbortWithError(c, err)
+			return
+		}
+		// Repositories run their statements with the request context, so the model hooks fill the audit columns
+		// from it.
+		c.Request = c.Request.WithContext(actor.WithUser(c.Request.Context(), userId))
 		c.Next()
 	}
 }
 
-func abortAuth(c *gin.Context, err error) {
-	c.AbortWithStatusJSON(
-		http.StatusUnauthorized,
-		helper.GenerateBaseResponseWithError(nil, false, helper.AuthError, err),
-	)
+func auditRejection(c *gin.Context, auditLogger services.AuditLoggerIface, reason string) {
+	auditLogger.Log(c.Request.Context(), services.AuditEvent{
+		Action: audit.AuthRejected,
+		After:  map[string]string{"reason": reason, "path": c.FullPath()},
+	})
 }
 
 func Authorization(validRoles []string) gin.HandlerFunc {
 	return func(context *gin.Context) {
 		if len(context.Keys) == 0 {
-			context.AbortWithStatusJSON(
-				http.StatusForbidden,
-				helper.GenerateBaseResponse(nil, false, helper.ForbiddenError),
-			)
+			helper.AbortWithError(context, service_errors.New(service_errors.ErrPermissionDenied))
 			return
 		}
 		rolesVal := context.Keys[constants.RolesKey]
 		if rolesVal == nil {
-			context.AbortWithStatusJSON(
-				http.StatusForbidden,
-				helper.GenerateBaseResponse(nil, false, helper.ForbiddenError),
-			)
+			helper.AbortWithError(context, service_errors.New(service_errors.ErrPermissionDenied))
 			return
 		}
 		roles := rolesVal.([]interface{})
@@ -80,9 +97,6 @@ func Authorization(validRoles []string) gin.HandlerFunc {
 				return
 			}
 		}
-		context.AbortWithStatusJSON(
-			http.StatusForbidden,
-			helper.GenerateBaseResponse(nil, false, helper.ForbiddenError),
-		)
+		helper.AbortWithError(context, service_errors.New(service_errors.ErrPermissionDenied))
 	}
 }
@@ -0,0 +1,21 @@
+package middlewares
+
+import (
+	"base_structure/src/api/helper"
+	"base_structure/src/config"
+	"base_structure/src/pkg/clientip"
+	"base_structure/src/pkg/logging"
+	"github.com/gin-gonic/gin"
+)
+
+// ClientIP resolves the client address once per request from the trusted proxies and headers of the server config.
+func ClientIP(cfg *config.Config) gin.HandlerFunc {
+	resolver, err := clientip.NewResolver(cfg.Server.TrustedProxies, cfg.Server.TrustedHeaders)
+	if err != nil {
+		logging.NewLogger(cfg).Fatal(logging.Internal, logging.StartUp, err.Error(), nil)
+	}
+	return func(c *gin.Context) {
+		helper.SetClientIP(c, resolver.ClientIP(c.Request))
+		c.Next()
+	}
+}
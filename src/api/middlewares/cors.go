@@ -9,7 +9,8 @@ func Cors(cfg *config.Config) gin.HandlerFunc {
 	return func(context *gin.Context) {
 		context.Writer.Header().Set("Access-Control-Allow-Origin", cfg.Cors.AllowOrigins)
 		context.Header("Access-Control-Allow-Credentials", "true")
-		context.Header("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
+		context.Header("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, X-Request-ID")
+		context.Header("Access-Control-Expose-Headers", "X-Request-ID")
 		context.Header("Access-Control-Allow-Methods", "POST, GET, OPTIONS, PUT, DELETE,UPDATE")
 		context.Header("Access-Control-Max-Age", "21600")
 		context.Set("content-type", "application/json")
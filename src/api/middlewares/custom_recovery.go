@@ -10,9 +10,9 @@ import (
 func ErrorHandler(c *gin.Context, err any) {
 	if err, ok := err.(error); ok {
 		httpResponse := helper.GenerateBaseResponseWithError(nil, false, helper.CustomRecovery, err)
-		c.AbortWithStatusJSON(http.StatusInternalServerError, httpResponse)
+		helper.AbortWithJSON(c, http.StatusInternalServerError, httpResponse)
 		return
 	}
 	httpResponse := helper.GenerateBaseResponseWithAnyError(nil, false, helper.CustomRecovery, err)
-	c.AbortWithStatusJSON(http.StatusInternalServerError, httpResponse)
+	helper.AbortWithJSON(c, http.StatusInternalServerError, httpResponse)
 }
@@ -0,0 +1,20 @@
+package middlewares
+
+import (
+	"base_structure/src/config"
+	"base_structure/src/pkg/i18n"
+	"github.com/gin-gonic/gin"
+)
+
+// Language picks the language of the response from the Accept-Language header, i18n.defaultLanguage when the client
+// accepts none of fa and en. It is stored in the request context and returned in the Content-Language header.
+func Language(cfg *config.Config) gin.HandlerFunc {
+	fallback := cfg.I18n.DefaultLanguage
+	return func(c *gin.Context) {
+		lang := i18n.Negotiate(c.GetHeader("Accept-Language"), fallback)
+		c.Header("Content-Language", lang)
+		c.Writer.Header().Add("Vary", "Accept-Language")
+		c.Request = c.Request.WithContext(i18n.WithLang(c.Request.Context(), lang))
+		c.Next()
+	}
+}
@@ -1,11 +1,14 @@
 package middlewares
 
 import (
+	"base_structure/src/api/helper"
+	"base_structure/src/config"
 	"base_structure/src/pkg/logging"
 	"bytes"
 	"github.com/gin-gonic/gin"
 	"io"
 	"mime"
+	"net/http"
 	"time"
 )
 
@@ -14,6 +17,14 @@ const (
 	skipContentType = "multipart/form-data"
 )
 
+// skipPaths are not logged: the swagger assets and the endpoints polled every few seconds.
+var skipPaths = map[string]struct{}{
+	"/swagger/*any": {},
+	"/healthz":      {},
+	"/readyz":       {},
+	"/metrics":      {},
+}
+
 type bodyLogWriter struct {
 	gin.ResponseWriter
 	buf *bytes.Buffer
@@ -27,9 +38,14 @@ func (w bodyLogWriter) Write(b []byte) (int, error) {
 	return n, err
 }
 
-func StructuredLogger(logger logging.Logger) gin.HandlerFunc {
+// StructuredLogger writes one line per request with its bodies, redacted according to logger.redaction.
+func StructuredLogger(cfg *config.Config, logger logging.Logger) gin.HandlerFunc {
+	redactor, err := logging.NewRedactor(cfg.Logger.Redaction)
+	if err != nil {
+		logger.Fatal(logging.Internal, logging.StartUp, err.Error(), nil)
+	}
 	return func(c *gin.Context) {
-		if c.FullPath() == "/swagger/*any" {
+		if _, skip := skipPaths[c.FullPath()]; skip {
 			c.Next()
 			return
 		}
@@ -42,7 +58,7 @@ func StructuredLogger(logger logging.Logger) gin.HandlerFunc {
 			if len(b) > maxBodySize {
 				reqBody = "(truncated)"
 			} else {
-				reqBody = string(b)
+				reqBody = redactor.Body(c.FullPath(), c.ContentType(), b)
 			}
 		}
 		blw := &bodyLogWriter{ResponseWriter: c.Writer, buf: bytes.NewBuffer(make([]byte, 0, 256))}
@@ -52,7 +68,7 @@ func StructuredLogger(logger logging.Logger) gin.HandlerFunc {
 		latency := time.Since(start)
 		fields := map[logging.ExtraKey]interface{}{
 			logging.Path:        c.FullPath(),
-			logging.ClientIp:    c.ClientIP(),
+			logging.ClientIp:    helper.ClientIP(c),
 			logging.Method:      c.Request.Method,
 			logging.StatusCode:  c.Writer.Status(),
 			logging.Latency:     latency,
@@ -60,11 +76,16 @@ func StructuredLogger(logger logging.Logger) gin.HandlerFunc {
 			logging.RequestBody: reqBody,
 		}
 		if em := c.Errors.ByType(gin.ErrorTypePrivate).String(); em != "" {
-			fields[logging.ErrorMessage] = em
+			fields[logging.ErrorMessage] = redactor.Text(em)
 		}
-		if ct, _, _ := mime.ParseMediaType(c.Writer.Header().Get("Content-Type")); ct == "application/json" && blw.buf.Len() < maxBodySize {
-			fields[logging.ResponseBody] = blw.buf.String()
+		if ct, _, _ := mime.ParseMediaType(c.Writer.Header().Get("Content-Type")); (ct == gin.MIMEJSON || ct == helper.ProblemContentType) && blw.buf.Len() < maxBodySize {
+			fields[logging.ResponseBody] = redactor.Body(c.FullPath(), ct, blw.buf.Bytes())
+		}
+		// Failed requests are logged as errors, which sampling never drops.
+		if c.Writer.Status() >= http.StatusInternalServerError {
+			logger.WithContext(c.Request.Context()).Error(logging.RequestResponse, logging.Api, "", fields)
+			return
 		}
-		logger.Info(logging.RequestResponse, logging.Api, "", fields)
+		logger.WithContext(c.Request.Context()).Info(logging.RequestResponse, logging.Api, "", fields)
 	}
 }
@@ -0,0 +1,29 @@
+package middlewares
+
+import (
+	"base_structure/src/api/helper"
+	"base_structure/src/pkg/metrics"
+	"github.com/gin-gonic/gin"
+	"strconv"
+	"time"
+)
+
+// Metrics records the rate, errors and duration of every request per route template, so /users/:id is one series
+// however many users there are. It is registered before the recovery middleware, so panicking requests are counted
+// with the 500 they end with.
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		resultCode := "none"
+		if code, ok := helper.RenderedResultCode(c); ok {
+			resultCode = strconv.Itoa(int(code))
+		}
+		metrics.HttpRequestDuration.WithLabelValues(c.Request.Method, route).Observe(time.Since(start).Seconds())
+		metrics.HttpRequests.WithLabelValues(c.Request.Method, route, strconv.Itoa(c.Writer.Status()), resultCode).Inc()
+	}
+}
@@ -0,0 +1,87 @@
+package middlewares
+
+import (
+	"base_structure/src/api/helper"
+	"base_structure/src/config"
+	"base_structure/src/constants"
+	"base_structure/src/pkg/audit"
+	"base_structure/src/pkg/service_errors"
+	"base_structure/src/services"
+	"context"
+	"encoding/json"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type recordingAuditLogger struct {
+	events []services.AuditEvent
+}
+
+func (a *recordingAuditLogger) Log(_ context.Context, event services.AuditEvent) {
+	a.events = append(a.events, event)
+}
+
+// serveProtected calls a route behind the authentication middleware with a token signed by secret, and reports
+// whether the handler was reached.
+func serveProtected(t *testing.T, auditLogger services.AuditLoggerIface, secret string, expiresAt time.Time) (*httptest.ResponseRecorder, bool) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	constants.AuthorizationHeaderKey = "Authorization"
+	t.Cleanup(func() { constants.AuthorizationHeaderKey = "" })
+	mr := miniredis.RunT(t)
+	cfg := *config.GetConfig()
+	cfg.Jwt.Secret = "secret"
+	cfg.Redis.Host, cfg.Redis.Port = mr.Host(), mr.Port()
+	reached := false
+	r := gin.New()
+	auth := authenticate(services.NewTokenService(&cfg), services.NewBlacklistService(&cfg), auditLogger)
+	r.GET("/protected", auth, func(c *gin.Context) {
+		reached = true
+		c.Status(http.StatusOK)
+	})
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"exp": expiresAt.Unix(),
+	}).SignedString([]byte(secret))
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/protected", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w, reached
+}
+
+func TestAuthenticationRejectsInvalidSignature(t *testing.T) {
+	auditLogger := &recordingAuditLogger{}
+	w, reached := serveProtected(t, auditLogger, "another secret", time.Now().Add(time.Hour))
+
+	assert.False(t, reached)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	var body helper.BaseHttpResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, 401, int(body.ResultCode)/100)
+	assert.Equal(t, service_errors.TokenInvalid, body.Error)
+	require.Len(t, auditLogger.events, 1)
+	assert.Equal(t, audit.AuthRejected, auditLogger.events[0].Action)
+	assert.Equal(t, "invalid token", auditLogger.events[0].After.(map[string]string)["reason"])
+}
+
+func TestAuthenticationRejectsExpiredToken(t *testing.T) {
+	auditLogger := &recordingAuditLogger{}
+	w, reached := serveProtected(t, auditLogger, "secret", time.Now().Add(-time.Minute))
+
+	assert.False(t, reached)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	var body helper.BaseHttpResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, service_errors.TokenExpired, body.Error)
+	require.Len(t, auditLogger.events, 1)
+	assert.Equal(t, "expired token", auditLogger.events[0].After.(map[string]string)["reason"])
+}
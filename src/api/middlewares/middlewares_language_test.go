@@ -0,0 +1,82 @@
+package middlewares
+
+import (
+	"base_structure/src/api/helper"
+	"base_structure/src/api/validations"
+	"base_structure/src/config"
+	"base_structure/src/pkg/i18n"
+	"base_structure/src/pkg/service_errors"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type languageRequest struct {
+	MobileNumber string `json:"mobileNumber" binding:"required"`
+}
+
+func newLanguageRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	if val, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		val.RegisterTagNameFunc(validations.FieldName)
+	}
+	cfg := &config.Config{I18n: config.I18nConfig{DefaultLanguage: i18n.Fa}}
+	r := gin.New()
+	r.Use(Language(cfg))
+	r.GET("/otp", func(c *gin.Context) {
+		helper.AbortWithError(c, service_errors.New(service_errors.ErrOtpUsed))
+	})
+	r.POST("/validate", func(c *gin.Context) {
+		err := c.ShouldBindJSON(new(languageRequest))
+		helper.AbortWithJSON(c, http.StatusUnprocessableEntity,
+			helper.GenerateBaseResponseWithValidationError(nil, false, helper.ValidationError, err))
+	})
+	return r
+}
+
+func serveLanguage(t *testing.T, method string, path string, acceptLanguage string) (*httptest.ResponseRecorder, helper.BaseHttpResponse) {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader("{}"))
+	req.Header.Set("Content-Type", gin.MIMEJSON)
+	if acceptLanguage != "" {
+		req.Header.Set("Accept-Language", acceptLanguage)
+	}
+	w := httptest.NewRecorder()
+	newLanguageRouter().ServeHTTP(w, req)
+	var body helper.BaseHttpResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	return w, body
+}
+
+func TestLanguageLocalizesServiceErrors(t *testing.T) {
+	w, body := serveLanguage(t, http.MethodGet, "/otp", "")
+
+	assert.Equal(t, i18n.Fa, w.Header().Get("Content-Language"))
+	assert.Equal(t, "کد یکبار مصرف قبلاً استفاده شده است", body.Error)
+
+	w, body = serveLanguage(t, http.MethodGet, "/otp", "en-US,en;q=0.9")
+
+	assert.Equal(t, i18n.En, w.Header().Get("Content-Language"))
+	assert.Equal(t, service_errors.OtpUsed, body.Error)
+}
+
+func TestLanguageLocalizesValidationErrorsWithJsonNames(t *testing.T) {
+	_, body := serveLanguage(t, http.MethodPost, "/validate", "fa-IR")
+
+	require.NotNil(t, body.ValidationErrors)
+	require.Len(t, *body.ValidationErrors, 1)
+	ve := (*body.ValidationErrors)[0]
+	assert.Equal(t, "mobileNumber", ve.Property)
+	assert.Equal(t, "mobileNumber الزامی است", ve.Message)
+
+	_, body = serveLanguage(t, http.MethodPost, "/validate", "en")
+
+	assert.Equal(t, "mobileNumber is required", (*body.ValidationErrors)[0].Message)
+}
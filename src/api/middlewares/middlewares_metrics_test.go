@@ -0,0 +1,43 @@
+package middlewares
+
+import (
+	"base_structure/src/api/helper"
+	"base_structure/src/pkg/metrics"
+	"base_structure/src/pkg/service_errors"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMetricsLabelsByRouteTemplateAndResultCode(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Metrics())
+	r.GET("/metrics-test/:id", func(c *gin.Context) {
+		helper.JSON(c, http.StatusNotFound, helper.GenerateBaseResponse(map[string]int{"resultCode": 1}, false, helper.TranslateErrorToResultCode(service_errors.ErrRecordNotFound)))
+	})
+
+	for _, path := range []string{"/metrics-test/1", "/metrics-test/2"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	counter := metrics.HttpRequests.WithLabelValues(http.MethodGet, "/metrics-test/:id", "404", "40401")
+	assert.Equal(t, float64(2), testutil.ToFloat64(counter))
+}
+
+func TestMetricsCountsPanickingRequests(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Metrics(), gin.CustomRecovery(ErrorHandler))
+	r.GET("/metrics-panic", func(c *gin.Context) {
+		panic("boom")
+	})
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/metrics-panic", nil))
+
+	counter := metrics.HttpRequests.WithLabelValues(http.MethodGet, "/metrics-panic", "500", "50001")
+	assert.Equal(t, float64(1), testutil.ToFloat64(counter))
+}
@@ -0,0 +1,100 @@
+package middlewares
+
+import (
+	"base_structure/src/config"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newRateLimitRouter(policy config.RateLimitPolicyConfig) *gin.Engine {
+	cfg := &config.Config{
+		Logger: config.LoggerConfig{Logger: "zap"},
+		RateLimit: config.RateLimitConfig{
+			Store:    "memory",
+			Policies: map[string]config.RateLimitPolicyConfig{"test": policy},
+		},
+	}
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/limited", RateLimit(cfg, "test"), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return r
+}
+
+func get(r *gin.Engine, remoteAddr string, hdr map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/limited", nil)
+	req.RemoteAddr = remoteAddr
+	for k, v := range hdr {
+		req.Header.Set(k, v)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestRateLimitSetsHeadersAndRejects(t *testing.T) {
+	r := newRateLimitRouter(config.RateLimitPolicyConfig{Rate: 2, Period: 60, Key: "ip"})
+
+	w := get(r, "192.0.2.1:1000", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "2;w=60", w.Header().Get("RateLimit-Policy"))
+	assert.Equal(t, "2", w.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "1", w.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "30", w.Header().Get("RateLimit-Reset"))
+
+	// another port of the same client shares its limit
+	w = get(r, "192.0.2.1:2000", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "0", w.Header().Get("RateLimit-Remaining"))
+
+	w = get(r, "192.0.2.1:1000", nil)
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "30", w.Header().Get("Retry-After"))
+	assert.Contains(t, w.Body.String(), "42901")
+
+	w = get(r, "192.0.2.2:1000", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestRateLimitKeyedByHeader(t *testing.T) {
+	r := newRateLimitRouter(config.RateLimitPolicyConfig{
+		Rate: 1, Period: 60, Key: "header", Header: "X-Tenant", Values: []string{"a", "b"},
+	})
+
+	assert.Equal(t, http.StatusOK, get(r, "192.0.2.1:1000", map[string]string{"X-Tenant": "a"}).Code)
+	assert.Equal(t, http.StatusOK, get(r, "192.0.2.1:1000", map[string]string{"X-Tenant": "b"}).Code)
+	assert.Equal(t, http.StatusTooManyRequests, get(r, "192.0.2.2:1000", map[string]string{"X-Tenant": "a"}).Code)
+
+	// without the header the client IP is the key
+	assert.Equal(t, http.StatusOK, get(r, "192.0.2.1:1000", nil).Code)
+	assert.Equal(t, http.StatusTooManyRequests, get(r, "192.0.2.1:1000", nil).Code)
+
+	// and so it is for unknown values, so changing the value on every request doesn't reset the limit
+	assert.Equal(t, http.StatusTooManyRequests, get(r, "192.0.2.1:1000", map[string]string{"X-Tenant": "c"}).Code)
+	assert.Equal(t, http.StatusTooManyRequests, get(r, "192.0.2.1:1000", map[string]string{"X-Tenant": "d"}).Code)
+}
+
+func TestRouteRateLimitAppliesTheRoutePolicy(t *testing.T) {
+	cfg := &config.Config{
+		Logger: config.LoggerConfig{Logger: "zap"},
+		RateLimit: config.RateLimitConfig{
+			Store:    "memory",
+			Policies: map[string]config.RateLimitPolicyConfig{"strict": {Rate: 1, Period: 60, Key: "ip"}},
+			Routes:   map[string]string{"users": "strict"},
+		},
+	}
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/limited", RouteRateLimit(cfg, "users"), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	assert.Equal(t, http.StatusOK, get(r, "10.0.0.1:1000", nil).Code)
+	w := get(r, "10.0.0.1:1000", nil)
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "1;w=60", w.Header().Get("RateLimit-Policy"))
+}
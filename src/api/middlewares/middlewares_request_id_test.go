@@ -0,0 +1,55 @@
+package middlewares
+
+import (
+	"base_structure/src/api/helper"
+	"base_structure/src/pkg/requestid"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newRequestIdRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(RequestId())
+	r.GET("/request-id-test", func(c *gin.Context) {
+		helper.JSON(c, http.StatusOK, helper.GenerateBaseResponse(nil, true, helper.Success))
+	})
+	return r
+}
+
+func serveRequestId(t *testing.T, r *gin.Engine, header string) (string, helper.BaseHttpResponse) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/request-id-test", nil)
+	if header != "" {
+		req.Header.Set(requestid.Header, header)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	var body helper.BaseHttpResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	return w.Header().Get(requestid.Header), body
+}
+
+func TestRequestIdReusesTheClientId(t *testing.T) {
+	id, body := serveRequestId(t, newRequestIdRouter(), "client-42:retry.1")
+
+	assert.Equal(t, "client-42:retry.1", id)
+	assert.Equal(t, id, body.RequestId)
+}
+
+func TestRequestIdReplacesMissingOrInvalidIds(t *testing.T) {
+	r := newRequestIdRouter()
+	for _, header := range []string{"", "has space", "line\nbreak", strings.Repeat("a", 65)} {
+		id, body := serveRequestId(t, r, header)
+
+		assert.NotEqual(t, header, id)
+		assert.True(t, requestid.Valid(id))
+		assert.Equal(t, id, body.RequestId)
+	}
+}
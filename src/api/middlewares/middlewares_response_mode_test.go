@@ -0,0 +1,112 @@
+package middlewares
+
+import (
+	"base_structure/src/api/helper"
+	"base_structure/src/config"
+	"base_structure/src/pkg/service_errors"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type validationRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+func newResponseModeRouter(mode string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	cfg := &config.Config{Server: config.ServerConfig{ResponseMode: mode, ProblemTypeBase: "https://errors.example.com/"}}
+	r := gin.New()
+	r.Use(RequestId(), ResponseMode(cfg))
+	r.GET("/ok", func(c *gin.Context) {
+		helper.JSON(c, http.StatusOK, helper.GenerateBaseResponse("done", true, helper.Success))
+	})
+	r.GET("/otp", func(c *gin.Context) {
+		helper.AbortWithError(c, service_errors.New(service_errors.ErrOtpUsed))
+	})
+	r.POST("/validate", func(c *gin.Context) {
+		err := c.ShouldBindJSON(new(validationRequest))
+		helper.AbortWithJSON(c, http.StatusUnprocessableEntity,
+			helper.GenerateBaseResponseWithValidationError(nil, false, helper.ValidationError, err))
+	})
+	return r
+}
+
+func serveResponseMode(r *gin.Engine, method string, path string, accept string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader("{}"))
+	req.Header.Set("Content-Type", gin.MIMEJSON)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestResponseModeEnvelopeKeepsBaseResponse(t *testing.T) {
+	w := serveResponseMode(newResponseModeRouter(helper.EnvelopeMode), http.MethodGet, "/otp", helper.ProblemContentType)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), gin.MIMEJSON)
+	var body helper.BaseHttpResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, helper.ResultCode(service_errors.ErrOtpUsed.Code), body.ResultCode)
+	assert.Equal(t, service_errors.OtpUsed, body.Error)
+}
+
+func TestResponseModeProblemRendersCatalogueErrors(t *testing.T) {
+	w := serveResponseMode(newResponseModeRouter(helper.ProblemMode), http.MethodGet, "/otp", "")
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Equal(t, helper.ProblemContentType, w.Header().Get("Content-Type"))
+	var body helper.ProblemDetails
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "https://errors.example.com/otp_used", body.Type)
+	assert.Equal(t, service_errors.OtpUsed, body.Title)
+	assert.Equal(t, http.StatusConflict, body.Status)
+	assert.Equal(t, "/otp", body.Instance)
+	assert.Equal(t, helper.ResultCode(service_errors.ErrOtpUsed.Code), body.ResultCode)
+	assert.NotEmpty(t, body.RequestId)
+}
+
+func TestResponseModeProblemRendersValidationErrors(t *testing.T) {
+	w := serveResponseMode(newResponseModeRouter(helper.ProblemMode), http.MethodPost, "/validate", "")
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	var body helper.ProblemDetails
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "about:blank", body.Type)
+	assert.Equal(t, http.StatusText(http.StatusUnprocessableEntity), body.Title)
+	assert.Equal(t, helper.ValidationError, body.ResultCode)
+	require.NotNil(t, body.ValidationErrors)
+	assert.NotEmpty(t, *body.ValidationErrors)
+}
+
+func TestResponseModeKeepsSuccessEnvelope(t *testing.T) {
+	for _, mode := range []string{helper.EnvelopeMode, helper.ProblemMode, helper.NegotiateMode} {
+		w := serveResponseMode(newResponseModeRouter(mode), http.MethodGet, "/ok", helper.ProblemContentType)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var body helper.BaseHttpResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.True(t, body.Success)
+		assert.Equal(t, "done", body.Result)
+	}
+}
+
+func TestResponseModeNegotiate(t *testing.T) {
+	r := newResponseModeRouter(helper.NegotiateMode)
+
+	problem := serveResponseMode(r, http.MethodGet, "/otp", helper.ProblemContentType)
+	assert.Equal(t, helper.ProblemContentType, problem.Header().Get("Content-Type"))
+
+	for _, accept := range []string{"", "*/*", gin.MIMEJSON} {
+		envelope := serveResponseMode(r, http.MethodGet, "/otp", accept)
+		assert.Contains(t, envelope.Header().Get("Content-Type"), gin.MIMEJSON, accept)
+	}
+}
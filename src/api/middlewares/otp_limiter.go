@@ -4,28 +4,29 @@ import (
 	"base_structure/src/api/helper"
 	"base_structure/src/config"
 	"base_structure/src/pkg/limiter"
-	"errors"
+	"base_structure/src/pkg/metrics"
+	"base_structure/src/pkg/service_errors"
+	"context"
 	"github.com/gin-gonic/gin"
 	"golang.org/x/time/rate"
-	"net/http"
 	"time"
 )
 
-func OtpLimiter(cfg *config.Config) gin.HandlerFunc {
-	var ipLimiter = limiter.NewIpRateLimiter(rate.Every(cfg.Otp.Limiter*time.Second), 1)
+// OtpLimiter allows one OTP request per client IP every otp.limiter seconds. Its buckets are dropped when ctx is done.
+func OtpLimiter(ctx context.Context, cfg *config.Config) gin.HandlerFunc {
+	var ipLimiter = limiter.NewIpRateLimiter(
+		ctx,
+		"otp",
+		rate.Every(cfg.Otp.Limiter*time.Second),
+		1,
+		cfg.Otp.LimiterMaxEntries,
+		cfg.Otp.LimiterIdleTimeout*time.Second,
+	)
 	return func(context *gin.Context) {
-		ipLimiter := ipLimiter.GetLimiter(context.Request.RemoteAddr)
+		ipLimiter := ipLimiter.GetLimiter(helper.ClientIP(context))
 		if !ipLimiter.Allow() {
-			context.AbortWithStatusJSON(
-				http.StatusTooManyRequests,
-				helper.GenerateBaseResponseWithError(
-					nil,
-					false,
-					helper.OtpLimiterError,
-					errors.New("not allowed"),
-				),
-			)
-			context.Abort()
+			metrics.RateLimitRejections.WithLabelValues("otp").Inc()
+			helper.AbortWithError(context, service_errors.New(service_errors.ErrOtpTooManyRequests))
 		} else {
 			context.Next()
 		}
@@ -0,0 +1,130 @@
+package middlewares
+
+import (
+	"base_structure/src/api/helper"
+	"base_structure/src/config"
+	"base_structure/src/constants"
+	"base_structure/src/data/cache"
+	"base_structure/src/pkg/limiter"
+	"base_structure/src/pkg/logging"
+	"base_structure/src/pkg/metrics"
+	"base_structure/src/pkg/service_errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"math"
+	"strconv"
+	"time"
+)
+
+// KeyFunc returns the value a request is counted under. An empty key falls back to the client IP.
+type KeyFunc func(c *gin.Context) string
+
+// RouteRateLimit enforces the policy the rateLimit.routes config section assigns to the named route group. A route
+// without a policy stops the server at startup, like a missing policy does.
+func RouteRateLimit(cfg *config.Config, route string) gin.HandlerFunc {
+	policy, ok := cfg.RateLimit.Routes[route]
+	if !ok {
+		logging.NewLogger(cfg).Fatal(logging.Internal, logging.RateLimit, fmt.Sprintf("no rate limit policy for route %q", route), nil)
+	}
+	return RateLimit(cfg, policy)
+}
+
+// RateLimit enforces the named policy of the rateLimit config section.
+func RateLimit(cfg *config.Config, policy string) gin.HandlerFunc {
+	return RateLimitWithKey(cfg, policy, nil)
+}
+
+// RateLimitWithKey enforces the named policy, counting requests under the key returned by key instead of the one
+// configured for the policy. Rejected requests get a 429 with Retry-After; when the store is unreachable the request
+// is let through, so an outage of Redis doesn't take the API down with it.
+func RateLimitWithKey(cfg *config.Config, policy string, key KeyFunc) gin.HandlerFunc {
+	logger := logging.NewLogger(cfg)
+	pc, ok := cfg.RateLimit.Policies[policy]
+	if !ok || pc.Rate <= 0 || pc.Period <= 0 {
+		logger.Fatal(logging.Internal, logging.RateLimit, fmt.Sprintf("rate limit policy %q is missing or invalid", policy), nil)
+	}
+	if key == nil {
+		key = policyKey(logger, policy, pc)
+	}
+	limit := limiter.Limit{Rate: pc.Rate, Period: pc.Period * time.Second, Burst: pc.Burst}
+	store := newRateLimitStore(cfg)
+	policyHeader := fmt.Sprintf("%d;w=%d", pc.Rate, int(pc.Period))
+	return func(c *gin.Context) {
+		k := key(c)
+		if k == "" {
+			k = "ip:" + helper.ClientIP(c)
+		}
+		res, err := store.Allow(c.Request.Context(), fmt.Sprintf("%s:%s:%s", constants.RedisRateLimitKey, policy, k), limit)
+		if err != nil {
+			logger.WithContext(c.Request.Context()).Error(logging.Redis, logging.RateLimit, err.Error(), nil)
+			c.Next()
+			return
+		}
+		c.Header("RateLimit-Policy", policyHeader)
+		c.Header("RateLimit-Limit", strconv.Itoa(res.Limit))
+		c.Header("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+		c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(res.ResetAfter)))
+		if !res.Allowed {
+			metrics.RateLimitRejections.WithLabelValues(policy).Inc()
+			c.Header("Retry-After", strconv.Itoa(ceilSeconds(res.RetryAfter)))
+			helper.AbortWithError(c, service_errors.New(service_errors.ErrTooManyRequests))
+			return
+		}
+		c.Next()
+	}
+}
+
+func policyKey(logger logging.Logger, policy string, pc config.RateLimitPolicyConfig) KeyFunc {
+	switch pc.Key {
+	case "", "ip":
+		return func(c *gin.Context) string {
+			return "ip:" + helper.ClientIP(c)
+		}
+	case "user":
+		// Anonymous requests on a user keyed route are counted per client IP.
+		return func(c *gin.Context) string {
+			if id, err := helper.GetUserId(c); err == nil {
+				return fmt.Sprintf("user:%d", id)
+			}
+			return ""
+		}
+	case "apiKey", "header":
+		if len(pc.Values) == 0 {
+			logger.Fatal(logging.Internal, logging.RateLimit, fmt.Sprintf("rate limit policy %q has no header values", policy), nil)
+		}
+		header := pc.Header
+		if pc.Key == "apiKey" {
+			header = constants.ApiKeyHeaderKey
+		}
+		return headerKey(pc.Key, header, pc.Values)
+	}
+	logger.Fatal(logging.Internal, logging.RateLimit, fmt.Sprintf("rate limit policy %q has unknown key %q", policy, pc.Key), nil)
+	return nil
+}
+
+// headerKey counts requests per value of header. The header is not verified, so only the configured values get a count
+// of their own; a client sending any other value, or none, is counted per client IP instead of escaping the limit.
+func headerKey(prefix, header string, values []string) KeyFunc {
+	known := make(map[string]struct{}, len(values))
+	for _, v := range values {
+		known[v] = struct{}{}
+	}
+	return func(c *gin.Context) string {
+		v := c.GetHeader(header)
+		if _, ok := known[v]; ok {
+			return prefix + ":" + v
+		}
+		return ""
+	}
+}
+
+func newRateLimitStore(cfg *config.Config) limiter.Store {
+	if cfg.RateLimit.Store == "memory" {
+		return limiter.NewMemoryStore()
+	}
+	return limiter.NewRedisStore(cache.GetRedis(cfg))
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,20 @@
+package middlewares
+
+import (
+	"base_structure/src/pkg/requestid"
+	"github.com/gin-gonic/gin"
+)
+
+// RequestId reuses the X-Request-ID header of the client when it is valid, or generates one. The id is returned in
+// the response header and stored in the request context, where the responses, logs and audit records read it.
+func RequestId() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(requestid.Header)
+		if !requestid.Valid(id) {
+			id = requestid.New()
+		}
+		c.Header(requestid.Header, id)
+		c.Request = c.Request.WithContext(requestid.WithId(c.Request.Context(), id))
+		c.Next()
+	}
+}
@@ -0,0 +1,22 @@
+package middlewares
+
+import (
+	"base_structure/src/api/helper"
+	"base_structure/src/pkg/audit"
+	"base_structure/src/pkg/requestid"
+	"github.com/gin-gonic/gin"
+)
+
+// RequestInfo stores the client address, user agent and request id in the request context, where the audit logger
+// picks them up.
+func RequestInfo() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		info := audit.RequestInfo{
+			Ip:        helper.ClientIP(c),
+			UserAgent: c.Request.UserAgent(),
+			RequestId: requestid.FromContext(c.Request.Context()),
+		}
+		c.Request = c.Request.WithContext(audit.WithRequestInfo(c.Request.Context(), info))
+		c.Next()
+	}
+}
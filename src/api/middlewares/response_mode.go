@@ -0,0 +1,21 @@
+package middlewares
+
+import (
+	"base_structure/src/api/helper"
+	"base_structure/src/config"
+	"github.com/gin-gonic/gin"
+)
+
+// ResponseMode selects how the errors of a request are rendered, following server.responseMode: in the
+// BaseHttpResponse envelope, always as problem details, or as problem details for clients that accept
+// application/problem+json. Success responses keep the envelope in every mode.
+func ResponseMode(cfg *config.Config) gin.HandlerFunc {
+	mode := cfg.Server.ResponseMode
+	typeBase := cfg.Server.ProblemTypeBase
+	return func(c *gin.Context) {
+		if mode == helper.ProblemMode || (mode == helper.NegotiateMode && helper.WantsProblemDetails(c)) {
+			helper.SetProblemDetails(c, typeBase)
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,21 @@
+package routers
+
+import (
+	"base_structure/src/api/handlers"
+	"base_structure/src/api/middlewares"
+	"base_structure/src/config"
+	"base_structure/src/constants"
+	"github.com/gin-gonic/gin"
+)
+
+func AuditLog(router *gin.RouterGroup, cfg *config.Config) {
+	h := handlers.NewAuditLogHandler(cfg)
+
+	// admin endpoints
+	admin := router.Group("").Use(
+		middlewares.Authentication(cfg),
+		middlewares.Authorization([]string{constants.AdminRoleName}),
+		middlewares.RouteRateLimit(cfg, "auditLogs"),
+	)
+	admin.GET("", h.List)
+}
@@ -0,0 +1,23 @@
+package routers
+
+import (
+	"base_structure/src/api/handlers"
+	"base_structure/src/api/middlewares"
+	"base_structure/src/config"
+	"base_structure/src/constants"
+	"expvar"
+	"github.com/gin-gonic/gin"
+)
+
+// Debug exposes the expvar variables, such as the size of the in-process rate limiters, and the log levels to admins.
+func Debug(router *gin.RouterGroup, cfg *config.Config) {
+	h := handlers.NewLogLevelHandler(cfg)
+
+	admin := router.Group("").Use(
+		middlewares.Authentication(cfg),
+		middlewares.Authorization([]string{constants.AdminRoleName}),
+	)
+	admin.GET("/vars", gin.WrapH(expvar.Handler()))
+	admin.GET("/log-level", h.Get)
+	admin.PUT("/log-level", h.Update)
+}
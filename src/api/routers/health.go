@@ -0,0 +1,16 @@
+package routers
+
+import (
+	"base_structure/src/api/handlers"
+	"base_structure/src/pkg/health"
+	"base_structure/src/pkg/lifecycle"
+	"github.com/gin-gonic/gin"
+)
+
+func Health(router *gin.RouterGroup, checks *health.Registry, hooks *lifecycle.Registry) {
+	h := handlers.NewHealthHandler(checks, hooks)
+
+	// probes
+	router.GET("/healthz", h.Live)
+	router.GET("/readyz", h.Ready)
+}
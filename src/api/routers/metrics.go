@@ -0,0 +1,10 @@
+package routers
+
+import (
+	"base_structure/src/pkg/metrics"
+	"github.com/gin-gonic/gin"
+)
+
+func Metrics(router *gin.RouterGroup) {
+	router.GET("/metrics", gin.WrapH(metrics.Handler()))
+}
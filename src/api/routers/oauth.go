@@ -0,0 +1,22 @@
+package routers
+
+import (
+	"base_structure/src/api/handlers"
+	"base_structure/src/api/middlewares"
+	"base_structure/src/config"
+	"github.com/gin-gonic/gin"
+)
+
+func OAuth(router *gin.RouterGroup, cfg *config.Config) {
+	h := handlers.NewOAuthHandler(cfg)
+
+	// public endpoints
+	router.GET("/:provider/login", h.Login)
+	router.GET("/:provider/callback", h.Callback)
+
+	// protected endpoints
+	auth := router.Group("").Use(middlewares.Authentication(cfg))
+	auth.POST("/:provider/link", h.Link)
+	auth.GET("/:provider/link/callback", h.LinkCallback)
+	auth.DELETE("/:provider", h.Unlink)
+}
@@ -4,14 +4,15 @@ import (
 	"base_structure/src/api/handlers"
 	"base_structure/src/api/middlewares"
 	"base_structure/src/config"
+	"context"
 	"github.com/gin-gonic/gin"
 )
 
-func User(router *gin.RouterGroup, cfg *config.Config) {
+func User(ctx context.Context, router *gin.RouterGroup, cfg *config.Config) {
 	h := handlers.NewUserHandler(cfg)
 
 	// public endpoints
-	router.POST("/send-otp", middlewares.OtpLimiter(cfg), h.SendOtp)
+	router.POST("/send-otp", middlewares.OtpLimiter(ctx, cfg), h.SendOtp)
 	router.POST("/login-by-username", h.LoginByUsername)
 	router.POST("/register-by-username", h.RegisterByUsername)
 	router.POST("/login-by-mobile", h.RegisterLoginByMobileNumber)
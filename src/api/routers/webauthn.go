@@ -0,0 +1,21 @@
+package routers
+
+import (
+	"base_structure/src/api/handlers"
+	"base_structure/src/api/middlewares"
+	"base_structure/src/config"
+	"github.com/gin-gonic/gin"
+)
+
+func WebAuthn(router *gin.RouterGroup, cfg *config.Config) {
+	h := handlers.NewWebAuthnHandler(cfg)
+
+	// public endpoints
+	router.POST("/login/begin", h.BeginLogin)
+	router.POST("/login/finish", h.FinishLogin)
+
+	// protected endpoints
+	auth := router.Group("").Use(middlewares.Authentication(cfg))
+	auth.POST("/register/begin", h.BeginRegistration)
+	auth.POST("/register/finish", h.FinishRegistration)
+}
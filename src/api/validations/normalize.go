@@ -0,0 +1,170 @@
+package validations
+
+import (
+	"base_structure/src/common"
+	"fmt"
+	"github.com/gin-gonic/gin/binding"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// normalizers are the names allowed in the normalize struct tag, e.g. `normalize:"digits,zerowidth"`.
+var normalizers = map[string]func(string) string{
+	"digits":    common.ToEnglishDigits,
+	"letters":   common.ToPersianLetters,
+	"zerowidth": common.StripZeroWidth,
+}
+
+// normalizingValidator normalizes the fields of a bound request before validating it, so Persian digits pass ir_mobile
+// and OTPs compare equal whatever keyboard they were typed on.
+type normalizingValidator struct {
+	next binding.StructValidator
+}
+
+// NewNormalizingValidator wraps the validator gin runs after binding a request. A validator that already normalizes is
+// returned as is.
+func NewNormalizingValidator(next binding.StructValidator) binding.StructValidator {
+	if _, ok := next.(*normalizingValidator); ok {
+		return next
+	}
+	return &normalizingValidator{next: next}
+}
+
+func (v *normalizingValidator) ValidateStruct(obj any) error {
+	Normalize(obj)
+	return v.next.ValidateStruct(obj)
+}
+
+func (v *normalizingValidator) Engine() any {
+	return v.next.Engine()
+}
+
+// Normalize rewrites the string, *string and []string fields of obj tagged with normalize, walking into nested structs,
+// pointers and slices. obj must be a pointer for the changes to stick. An unknown normalizer name panics, as it is a
+// mistake in the DTO that CheckNormalizeTags reports at start up.
+func Normalize(obj any) {
+	normalizeValue(reflect.ValueOf(obj))
+}
+
+// CheckNormalizeTags parses the normalize tags of the types of objs and of the structs they hold, returning the first
+// unknown normalizer name.
+func CheckNormalizeTags(objs ...any) error {
+	seen := map[reflect.Type]bool{}
+	for _, obj := range objs {
+		if err := checkType(reflect.TypeOf(obj), seen); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkType(t reflect.Type, seen map[reflect.Type]bool) error {
+	for t != nil && (t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct || seen[t] {
+		return nil
+	}
+	seen[t] = true
+	plan, err := structPlan(t)
+	if err != nil {
+		return err
+	}
+	for _, p := range plan {
+		if p.fns == nil {
+			if err := checkType(t.Field(p.index).Type, seen); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// fieldPlan is how Normalize treats an exported field of a struct: it applies fns when the field is tagged and walks
+// into it otherwise.
+type fieldPlan struct {
+	index int
+	fns   []func(string) string
+}
+
+// plans caches the parsed tags of each struct type, keyed by reflect.Type.
+var plans sync.Map
+
+func structPlan(t reflect.Type) ([]fieldPlan, error) {
+	if plan, ok := plans.Load(t); ok {
+		return plan.([]fieldPlan), nil
+	}
+	var plan []fieldPlan
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		p := fieldPlan{index: i}
+		if tag, ok := f.Tag.Lookup("normalize"); ok {
+			fns, err := fieldNormalizers(t, f, tag)
+			if err != nil {
+				return nil, err
+			}
+			p.fns = fns
+		}
+		plan = append(plan, p)
+	}
+	plans.Store(t, plan)
+	return plan, nil
+}
+
+func normalizeValue(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if !v.IsNil() {
+			normalizeValue(v.Elem())
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			normalizeValue(v.Index(i))
+		}
+	case reflect.Struct:
+		plan, err := structPlan(v.Type())
+		if err != nil {
+			panic(err.Error())
+		}
+		for _, p := range plan {
+			if p.fns != nil {
+				normalizeField(v.Field(p.index), p.fns)
+				continue
+			}
+			normalizeValue(v.Field(p.index))
+		}
+	}
+}
+
+func fieldNormalizers(t reflect.Type, f reflect.StructField, tag string) ([]func(string) string, error) {
+	var fns []func(string) string
+	for _, name := range strings.Split(tag, ",") {
+		fn, ok := normalizers[strings.TrimSpace(name)]
+		if !ok {
+			return nil, fmt.Errorf("unknown normalizer %q on %s.%s", name, t.Name(), f.Name)
+		}
+		fns = append(fns, fn)
+	}
+	return fns, nil
+}
+
+func normalizeField(v reflect.Value, fns []func(string) string) {
+	switch {
+	case v.Kind() == reflect.String && v.CanSet():
+		s := v.String()
+		for _, fn := range fns {
+			s = fn(s)
+		}
+		v.SetString(s)
+	case v.Kind() == reflect.Pointer && !v.IsNil():
+		normalizeField(v.Elem(), fns)
+	case v.Kind() == reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			normalizeField(v.Index(i), fns)
+		}
+	}
+}
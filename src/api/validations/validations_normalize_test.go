@@ -0,0 +1,95 @@
+package validations
+
+import (
+	"base_structure/src/common"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type normalizeItem struct {
+	Code string `normalize:"digits"`
+}
+
+type normalizeRequest struct {
+	Mobile   string          `normalize:"digits,zerowidth"`
+	Name     string          `normalize:"letters"`
+	Raw      string          // left alone
+	Optional *string         `normalize:"digits"`
+	Codes    []string        `normalize:"digits"`
+	Item     normalizeItem   // nested structs are walked
+	Items    []normalizeItem // and so are their slices
+	Nested   *normalizeItem
+}
+
+func TestNormalizeStrings(t *testing.T) {
+	assert.Equal(t, "09123456789", common.ToEnglishDigits("۰۹۱۲۳۴۵۶۷۸۹"))
+	assert.Equal(t, "09123456789", common.ToEnglishDigits("٠٩١٢٣٤٥٦٧٨٩"))
+	assert.Equal(t, "علی کاظمی", common.ToPersianLetters("علي كاظمى"))
+	assert.Equal(t, "123456", common.StripZeroWidth("\u200f123\u200c456\ufeff"))
+}
+
+func TestNormalizeFollowsTags(t *testing.T) {
+	optional := "۱۲"
+	req := &normalizeRequest{
+		Mobile:   "\u200e۰۹۱۲۳۴۵۶۷۸۹",
+		Name:     "علي",
+		Raw:      "۱۲",
+		Optional: &optional,
+		Codes:    []string{"۱", "٢"},
+		Item:     normalizeItem{Code: "۳"},
+		Items:    []normalizeItem{{Code: "۴"}},
+		Nested:   &normalizeItem{Code: "۵"},
+	}
+
+	Normalize(req)
+
+	assert.Equal(t, "09123456789", req.Mobile)
+	assert.Equal(t, "علی", req.Name)
+	assert.Equal(t, "۱۲", req.Raw)
+	assert.Equal(t, "12", *req.Optional)
+	assert.Equal(t, []string{"1", "2"}, req.Codes)
+	assert.Equal(t, "3", req.Item.Code)
+	assert.Equal(t, "4", req.Items[0].Code)
+	assert.Equal(t, "5", req.Nested.Code)
+}
+
+func TestCheckNormalizeTagsReportsUnknownNormalizer(t *testing.T) {
+	type badItem struct {
+		Value string `normalize:"digits,upper"`
+	}
+	req := &struct {
+		Items []*badItem
+	}{}
+
+	require.NoError(t, CheckNormalizeTags(&normalizeRequest{}, normalizeItem{}))
+	assert.EqualError(t, CheckNormalizeTags(&normalizeRequest{}, req), `unknown normalizer "upper" on badItem.Value`)
+	assert.Panics(t, func() { Normalize(&badItem{}) })
+}
+
+func TestNormalizingValidatorRunsBeforeValidation(t *testing.T) {
+	next := binding.Validator
+	binding.Validator = NewNormalizingValidator(next)
+	t.Cleanup(func() { binding.Validator = next })
+	assert.Same(t, binding.Validator, NewNormalizingValidator(binding.Validator))
+	val, ok := binding.Validator.Engine().(*validator.Validate)
+	require.True(t, ok)
+	require.NoError(t, val.RegisterValidation("ir_mobile", IranianMobileNumberValidator, true))
+
+	var req struct {
+		MobileNumber string `json:"mobileNumber" binding:"required,ir_mobile,min=11,max=11" normalize:"digits,zerowidth"`
+	}
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"mobileNumber":"۰۹۱۲\u200c۳۴۵۶۷۸۹"}`))
+	c.Request.Header.Set("Content-Type", gin.MIMEJSON)
+
+	require.NoError(t, c.ShouldBindJSON(&req))
+	assert.Equal(t, "09123456789", req.MobileNumber)
+}
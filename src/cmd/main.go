@@ -7,6 +7,18 @@ import (
 	"base_structure/src/data/cache"
 	"base_structure/src/data/db"
 	"base_structure/src/data/db/migrations"
+	"base_structure/src/pkg/health"
+	"base_structure/src/pkg/lifecycle"
+	"base_structure/src/pkg/logging"
+	"base_structure/src/pkg/tracing"
+	"base_structure/src/services"
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 )
 
 // @securityDefinitions.apikey BearerAuth
@@ -14,12 +26,99 @@ import (
 // @name                        Authorization
 // @description                 "JWT: Bearer <token>"
 func main() {
+	flag.Parse()
 	cfg := config.GetConfig()
 	constants.InitConstants()
-	cache.GetRedis(cfg)
-	defer cache.CloseRedis()
-	db.GetDb(cfg)
-	migrations.Up1(cfg)
-	defer db.CloseDb()
-	api.InitServer(cfg)
+	switch cmd := flag.Arg(0); cmd {
+	case "", "serve":
+		serve(cfg)
+	case "migrate":
+		if err := migrate(cfg, flag.Args()[1:]); err != nil {
+			log.Fatal(err)
+		}
+	case "seed":
+		if err := seed(cfg, flag.Args()[1:]); err != nil {
+			log.Fatal(err)
+		}
+	default:
+		log.Fatalf("unknown command %q: expected serve, migrate or seed", cmd)
+	}
+}
+
+// serve runs the API until SIGINT or SIGTERM. SIGHUP reloads the log levels from the config file. A second signal kills the process without waiting for the drain.
+func serve(cfg *config.Config) {
+	logger := logging.NewLogger(cfg)
+	hooks := lifecycle.New()
+	hooks.OnStop("logger", func(context.Context) error {
+		return logger.Sync()
+	})
+	shutdownTracing, err := tracing.Init(context.Background(), cfg)
+	if err != nil {
+		logger.Fatal(logging.Internal, logging.StartUp, err.Error(), nil)
+	}
+	hooks.OnStop("tracing", shutdownTracing)
+	redisClient := cache.GetRedis(cfg)
+	hooks.OnStop("redis", func(context.Context) error {
+		return cache.CloseRedis()
+	})
+	database := db.GetDb(cfg)
+	hooks.OnStop("postgres", func(context.Context) error {
+		return db.CloseDb()
+	})
+	auditLogger := services.GetAuditLogger(cfg)
+	hooks.OnStop("audit logger", func(context.Context) error {
+		auditLogger.Close()
+		return nil
+	})
+
+	checks := health.NewRegistry(cfg.Health.CheckTimeout * time.Second)
+	checks.Register(
+		db.HealthChecker(database),
+		cache.HealthChecker(redisClient),
+		migrations.HealthChecker(database),
+	)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+	go reloadLogLevels(ctx, logger)
+	serveErr := api.InitServer(ctx, cfg, hooks, checks)
+
+	stopCtx, cancel := context.WithTimeout(context.Background(), api.ShutdownTimeout(cfg))
+	defer cancel()
+	if err := hooks.Stop(stopCtx); err != nil {
+		log.Printf("shutdown: %v", err)
+	}
+	if serveErr != nil {
+		os.Exit(1)
+	}
+}
+
+// reloadLogLevels applies logger.level and logger.categories from the config file on every SIGHUP until ctx is done.
+func reloadLogLevels(ctx context.Context, logger logging.Logger) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			cfg, err := config.Reload()
+			if err == nil {
+				err = logging.ApplyConfig(cfg.Logger)
+			}
+			if err != nil {
+				logger.Error(logging.Internal, logging.LogLevel, err.Error(), nil)
+				continue
+			}
+			levels := logging.Levels()
+			logger.Warn(logging.Internal, logging.LogLevel, "log levels reloaded", map[logging.ExtraKey]interface{}{
+				logging.GlobalLevel:    levels.Level,
+				logging.CategoryLevels: levels.Categories,
+			})
+		}
+	}
 }
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"base_structure/src/config"
+	"base_structure/src/data/db"
+	"base_structure/src/data/db/migrations"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+)
+
+const migrateUsage = "usage: migrate up | down | to <version> | status"
+
+// migrate runs a migrate subcommand. It returns its error rather than exiting, so the connection is closed first.
+func migrate(cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+	database := db.GetDb(cfg)
+	defer db.CloseDb()
+	m := migrations.NewMigrator(database)
+
+	var err error
+	switch args[0] {
+	case "up":
+		err = m.Up()
+	case "down":
+		err = m.Down()
+	case "to":
+		if len(args) != 2 {
+			return errors.New(migrateUsage)
+		}
+		version, perr := strconv.Atoi(args[1])
+		if perr != nil {
+			return fmt.Errorf("invalid version %q", args[1])
+		}
+		err = m.To(version)
+	case "status":
+		err = printStatus(m)
+	default:
+		return errors.New(migrateUsage)
+	}
+	if err != nil {
+		return fmt.Errorf("migrate %s: %w", args[0], err)
+	}
+	return nil
+}
+
+func printStatus(m *migrations.Migrator) error {
+	statuses, err := m.Status()
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+	for _, st := range statuses {
+		state, appliedAt := "pending", ""
+		if st.Applied {
+			state = "applied"
+			appliedAt = st.AppliedAt.Format(time.RFC3339)
+		}
+		if st.ChecksumMismatch {
+			state = "modified"
+		}
+		_, _ = fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", st.Version, st.Name, state, appliedAt)
+	}
+	return w.Flush()
+}
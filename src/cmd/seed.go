@@ -0,0 +1,29 @@
+package main
+
+import (
+	"base_structure/src/config"
+	"base_structure/src/data/db"
+	"base_structure/src/data/db/seeders"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// seed runs the seeders of the profile given as argument, or of APP_ENV when none is given. It returns its error rather
+// than exiting, so the connection is closed first.
+func seed(cfg *config.Config, args []string) error {
+	profile := os.Getenv("APP_ENV")
+	if len(args) > 0 {
+		profile = args[0]
+	}
+	if profile == "" {
+		return fmt.Errorf("usage: seed <profile> (one of %s)", strings.Join(seeders.Profiles(), ", "))
+	}
+	database := db.GetDb(cfg)
+	defer db.CloseDb()
+	if err := seeders.Seed(context.Background(), database, profile); err != nil {
+		return fmt.Errorf("seed %s: %w", profile, err)
+	}
+	return nil
+}
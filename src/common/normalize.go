@@ -0,0 +1,42 @@
+package common
+
+import "strings"
+
+// ToEnglishDigits replaces Persian (۰-۹) and Arabic-Indic (٠-٩) digits with ASCII ones.
+func ToEnglishDigits(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= '۰' && r <= '۹':
+			return '0' + (r - '۰')
+		case r >= '٠' && r <= '٩':
+			return '0' + (r - '٠')
+		}
+		return r
+	}, s)
+}
+
+// ToPersianLetters replaces the Arabic yeh (ي, ى) and kaf (ك) with their Persian forms (ی, ک), so text typed on either
+// keyboard compares equal.
+func ToPersianLetters(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case 'ي', 'ى':
+			return 'ی'
+		case 'ك':
+			return 'ک'
+		}
+		return r
+	}, s)
+}
+
+// StripZeroWidth removes zero-width spaces, joiners and non-joiners, direction marks and byte order marks. Persian
+// words use the zero-width non-joiner (می‌خواهم), so it is meant for codes and numbers rather than free text.
+func StripZeroWidth(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '\u200b', '\u200c', '\u200d', '\u200e', '\u200f', '\u2060', '\ufeff':
+			return -1
+		}
+		return r
+	}, s)
+}
@@ -15,26 +15,83 @@ var (
 )
 
 type Config struct {
-	Server   ServerConfig
-	Logger   LoggerConfig
-	Postgres PostgresConfig
-	Redis    RedisConfig
-	Password PasswordConfig
-	Cors     CorsConfig
-	Otp      OtpConfig
-	Jwt      JwtConfig
+	Server    ServerConfig
+	Logger    LoggerConfig
+	Postgres  PostgresConfig
+	Redis     RedisConfig
+	Password  PasswordConfig
+	Cors      CorsConfig
+	Otp       OtpConfig
+	Jwt       JwtConfig
+	WebAuthn  WebAuthnConfig
+	OAuth     OAuthConfig
+	Audit     AuditConfig
+	RateLimit RateLimitConfig
+	Health    HealthConfig
+	Tracing   TracingConfig
+	I18n      I18nConfig
 }
 
+// ServerConfig holds the HTTP server settings.
 type ServerConfig struct {
-	Port    string
-	RunMode string
+	Port              string
+	RunMode           string
+	TrustedProxies    []string
+	TrustedHeaders    []string
+	ReadTimeout       time.Duration
+	ReadHeaderTimeout time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	ShutdownTimeout   time.Duration
+	DrainDelay        time.Duration
+	ResponseMode      string
+	ProblemTypeBase   string
 }
 
+// LoggerConfig selects the logging backend, its output and the levels, globally and per category.
 type LoggerConfig struct {
-	FilePath string
-	Encoding string
-	Level    string
-	Logger   string
+	FilePath    string
+	Encoding    string
+	Sinks       []string
+	MaxSize     int
+	MaxBackups  int
+	MaxAge      int
+	Level       string
+	Categories  map[string]string
+	Logger      string
+	Redaction   RedactionConfig
+	Sampling    LogSamplingConfig
+	DedupWindow time.Duration
+}
+
+// LogSamplingConfig holds the sampling rules of the non-error log levels and categories.
+type LogSamplingConfig struct {
+	Interval   time.Duration
+	Levels     map[string]LogSamplingRule
+	Categories map[string]LogSamplingRule
+}
+
+type LogSamplingRule struct {
+	First      int
+	Thereafter int
+}
+
+// RedactionConfig controls what the request and response bodies in the logs keep.
+type RedactionConfig struct {
+	Mode        string
+	Mask        string
+	Fields      []string
+	AllowFields []string
+	MaskMobile  bool
+	MaskEmail   bool
+	Patterns    []string
+	Routes      []RedactionRouteConfig
+}
+
+type RedactionRouteConfig struct {
+	Path        string
+	Fields      []string
+	AllowFields []string
 }
 
 type PostgresConfig struct {
@@ -77,9 +134,11 @@ type CorsConfig struct {
 }
 
 type OtpConfig struct {
-	ExpireTime time.Duration
-	Digits     int
-	Limiter    time.Duration
+	ExpireTime         time.Duration
+	Digits             int
+	Limiter            time.Duration
+	LimiterMaxEntries  int
+	LimiterIdleTimeout time.Duration
 }
 
 type JwtConfig struct {
@@ -89,6 +148,74 @@ type JwtConfig struct {
 	RefreshSecret              string
 }
 
+type WebAuthnConfig struct {
+	RPID                string
+	RPDisplayName       string
+	RPOrigins           []string
+	ChallengeExpireTime time.Duration
+}
+
+type OAuthConfig struct {
+	StateExpireTime time.Duration
+	Providers       map[string]OAuthProviderConfig
+}
+
+type AuditConfig struct {
+	BufferSize    int
+	BatchSize     int
+	FlushInterval time.Duration
+}
+
+// I18nConfig sets the language of the responses whose Accept-Language header names no supported language (fa or en).
+type I18nConfig struct {
+	DefaultLanguage string
+}
+
+// TracingConfig selects the span exporter: otlp, stdout or none. Endpoint is the host:port of the OTLP HTTP
+// collector and SampleRatio the share of new traces that are recorded.
+type TracingConfig struct {
+	Exporter    string
+	Endpoint    string
+	Insecure    bool
+	ServiceName string
+	SampleRatio float64
+}
+
+// HealthConfig bounds every readiness check to CheckTimeout seconds.
+type HealthConfig struct {
+	CheckTimeout time.Duration
+}
+
+// RateLimitConfig holds the named rate limit policies and the policy of each route group.
+type RateLimitConfig struct {
+	Store    string
+	Policies map[string]RateLimitPolicyConfig
+	Routes   map[string]string
+}
+
+// RateLimitPolicyConfig is a GCRA limit and the key requests are counted under.
+type RateLimitPolicyConfig struct {
+	Rate   int
+	Period time.Duration
+	Burst  int
+	Key    string
+	Header string
+	Values []string
+}
+
+type OAuthProviderConfig struct {
+	ClientId     string
+	ClientSecret string
+	RedirectUrl  string
+	Scopes       []string
+	Issuer       string
+	AuthUrl      string
+	TokenUrl     string
+	UserInfoUrl  string
+	SubjectField string
+	EmailField   string
+}
+
 func LoadDotEnv() {
 	startDir, err := os.Getwd()
 	if err != nil {
@@ -111,6 +238,16 @@ func LoadDotEnv() {
 	}
 }
 
+// Reload reads the config file again. The config returned by GetConfig is left untouched: components built from it
+// keep their settings, and only what the caller applies from the new config changes at runtime.
+func Reload() (*Config, error) {
+	v, err := resolveConfig()
+	if err != nil {
+		return nil, err
+	}
+	return ParseConfig(v)
+}
+
 func GetConfig() *Config {
 	once.Do(func() {
 		LoadDotEnv()
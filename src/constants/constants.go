@@ -14,7 +14,11 @@ var (
 	AdminPassword          string
 	AdminEmail             string
 	RedisOtpDefaultKey     string
+	RedisWebAuthnKey       string
+	RedisOAuthStateKey     string
+	RedisRateLimitKey      string
 	AuthorizationHeaderKey string
+	ApiKeyHeaderKey        string
 	UserIdKey              string
 	FirstNameKey           string
 	LastNameKey            string
@@ -35,7 +39,11 @@ func InitConstants() {
 	AdminPassword = os.Getenv("ADMIN_PASSWORD")
 	AdminEmail = os.Getenv("ADMIN_EMAIL")
 	RedisOtpDefaultKey = os.Getenv("REDIS_OTP_DEFAULT_KEY")
+	RedisWebAuthnKey = os.Getenv("REDIS_WEBAUTHN_KEY")
+	RedisOAuthStateKey = os.Getenv("REDIS_OAUTH_STATE_KEY")
+	RedisRateLimitKey = os.Getenv("REDIS_RATE_LIMIT_KEY")
 	AuthorizationHeaderKey = os.Getenv("AUTHORIZATION_HEADER_KEY")
+	ApiKeyHeaderKey = os.Getenv("API_KEY_HEADER_KEY")
 	UserIdKey = os.Getenv("USER_ID_KEY")
 	FirstNameKey = os.Getenv("FIRST_NAME_KEY")
 	LastNameKey = os.Getenv("LAST_NAME_KEY")
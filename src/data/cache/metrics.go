@@ -0,0 +1,51 @@
+package cache
+
+import (
+	"base_structure/src/pkg/metrics"
+	"context"
+	"errors"
+	"github.com/go-redis/redis/v7"
+	"time"
+)
+
+type metricsStartKey struct{}
+
+// metricsHook times every Redis command into metrics.RedisCommandDuration. A pipeline is observed as one command
+// named pipeline.
+type metricsHook struct{}
+
+func (metricsHook) BeforeProcess(ctx context.Context, _ redis.Cmder) (context.Context, error) {
+	return context.WithValue(ctx, metricsStartKey{}, time.Now()), nil
+}
+
+func (metricsHook) AfterProcess(ctx context.Context, cmd redis.Cmder) error {
+	observeCommand(ctx, cmd.Name(), cmd.Err())
+	return nil
+}
+
+func (metricsHook) BeforeProcessPipeline(ctx context.Context, _ []redis.Cmder) (context.Context, error) {
+	return context.WithValue(ctx, metricsStartKey{}, time.Now()), nil
+}
+
+func (metricsHook) AfterProcessPipeline(ctx context.Context, cmds []redis.Cmder) error {
+	var err error
+	for _, cmd := range cmds {
+		if cmd.Err() != nil && !errors.Is(cmd.Err(), redis.Nil) {
+			err = cmd.Err()
+		}
+	}
+	observeCommand(ctx, "pipeline", err)
+	return nil
+}
+
+func observeCommand(ctx context.Context, name string, err error) {
+	start, ok := ctx.Value(metricsStartKey{}).(time.Time)
+	if !ok {
+		return
+	}
+	result := metrics.Success
+	if err != nil && !errors.Is(err, redis.Nil) {
+		result = metrics.Failure
+	}
+	metrics.RedisCommandDuration.WithLabelValues(name, result).Observe(time.Since(start).Seconds())
+}
@@ -2,7 +2,9 @@ package cache
 
 import (
 	"base_structure/src/config"
+	"base_structure/src/pkg/health"
 	"base_structure/src/pkg/logging"
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/go-redis/redis/v7"
@@ -29,6 +31,8 @@ func InitRedis(cfg *config.Config) error {
 			IdleTimeout:        cfg.Redis.IdleTimeout * time.Millisecond,
 			IdleCheckFrequency: cfg.Redis.IdleCheckFrequency * time.Millisecond,
 		})
+		redisClient.AddHook(metricsHook{})
+		redisClient.AddHook(tracingHook{})
 		_, err = redisClient.Ping().Result()
 		if err != nil {
 			redisClient = nil
@@ -50,28 +54,35 @@ func GetRedis(cfg *config.Config) *redis.Client {
 	return redisClient
 }
 
-func CloseRedis() {
+func CloseRedis() error {
 	if redisClient != nil {
 		err := redisClient.Close()
 		if err != nil {
-			logger.Fatal(logging.Redis, logging.Closing, "error on closing redis connection", nil)
-			return
+			logger.Error(logging.Redis, logging.Closing, "error on closing redis connection", nil)
+			return err
 		}
 		redisClient = nil
 	}
+	return nil
+}
+
+func HealthChecker(client *redis.Client) health.HealthChecker {
+	return health.NewChecker("redis", func(ctx context.Context) error {
+		return client.WithContext(ctx).Ping().Err()
+	})
 }
 
-func Set[T any](c *redis.Client, key string, value T, duration time.Duration) error {
+func Set[T any](ctx context.Context, c *redis.Client, key string, value T, duration time.Duration) error {
 	v, err := json.Marshal(value)
 	if err != nil {
 		return err
 	}
-	return c.Set(key, v, duration).Err()
+	return c.WithContext(ctx).Set(key, v, duration).Err()
 }
 
-func Get[T any](c *redis.Client, key string) (T, error) {
+func Get[T any](ctx context.Context, c *redis.Client, key string) (T, error) {
 	dest := *new(T)
-	v, err := c.Get(key).Result()
+	v, err := c.WithContext(ctx).Get(key).Result()
 	if err != nil {
 		return dest, err
 	}
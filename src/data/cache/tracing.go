@@ -0,0 +1,53 @@
+package cache
+
+import (
+	"base_structure/src/pkg/tracing"
+	"context"
+	"errors"
+	"github.com/go-redis/redis/v7"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracingHook creates a client span for every Redis command or pipeline.
+type tracingHook struct{}
+
+func (tracingHook) BeforeProcess(ctx context.Context, cmd redis.Cmder) (context.Context, error) {
+	return startSpan(ctx, "redis."+cmd.Name(), cmd.Name()), nil
+}
+
+func (tracingHook) AfterProcess(ctx context.Context, cmd redis.Cmder) error {
+	endSpan(ctx, cmd.Err())
+	return nil
+}
+
+func (tracingHook) BeforeProcessPipeline(ctx context.Context, _ []redis.Cmder) (context.Context, error) {
+	return startSpan(ctx, "redis.pipeline", "pipeline"), nil
+}
+
+func (tracingHook) AfterProcessPipeline(ctx context.Context, cmds []redis.Cmder) error {
+	var err error
+	for _, cmd := range cmds {
+		if cmd.Err() != nil {
+			err = cmd.Err()
+		}
+	}
+	endSpan(ctx, err)
+	return nil
+}
+
+func startSpan(ctx context.Context, name string, operation string) context.Context {
+	ctx, _ = tracing.Tracer().Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemNameRedis, semconv.DBOperationName(operation)),
+	)
+	return ctx
+}
+
+// endSpan ends the span started by the hook. A missing key (redis.Nil) is a normal outcome, not a failure.
+func endSpan(ctx context.Context, err error) {
+	if errors.Is(err, redis.Nil) {
+		err = nil
+	}
+	tracing.End(trace.SpanFromContext(ctx), err)
+}
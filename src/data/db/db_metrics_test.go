@@ -0,0 +1,25 @@
+package db
+
+import (
+	"base_structure/src/data/models"
+	"base_structure/src/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestMetricsPluginTimesStatements(t *testing.T) {
+	database := newTestDb(t)
+	require.NoError(t, database.Use(MetricsPlugin{}))
+
+	before := testutil.CollectAndCount(metrics.DbQueryDuration)
+	require.NoError(t, database.Create(&models.Role{Name: "metrics"}).Error)
+	var roles []models.Role
+	require.NoError(t, database.Find(&roles).Error)
+
+	assert.Greater(t, testutil.CollectAndCount(metrics.DbQueryDuration), before)
+	observer := metrics.DbQueryDuration.WithLabelValues("create", "roles", metrics.Success)
+	assert.Equal(t, 1, testutil.CollectAndCount(observer.(prometheus.Histogram)))
+}
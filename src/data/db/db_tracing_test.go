@@ -0,0 +1,39 @@
+package db
+
+import (
+	"base_structure/src/data/models"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"testing"
+)
+
+func TestTracingPluginCreatesChildSpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() {
+		_ = provider.Shutdown(context.Background())
+	})
+	database := newTestDb(t)
+	require.NoError(t, database.Use(TracingPlugin{}))
+
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "parent")
+	require.NoError(t, Conn(ctx, database).Create(&models.Role{Name: "tracing"}).Error)
+	var roles []models.Role
+	require.NoError(t, Conn(ctx, database).Find(&roles).Error)
+	parent.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+	assert.Equal(t, "gorm.create", spans[0].Name())
+	assert.Equal(t, "gorm.query", spans[1].Name())
+	for _, span := range spans[:2] {
+		assert.Equal(t, parent.SpanContext().SpanID(), span.Parent().SpanID())
+		assert.Contains(t, span.Attributes(), semconv.DBCollectionName("roles"))
+	}
+}
@@ -0,0 +1,91 @@
+package db
+
+import (
+	"base_structure/src/data/models"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"testing"
+)
+
+func newTestDb(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())
+	database, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, database.AutoMigrate(&models.Role{}))
+	t.Cleanup(func() {
+		sqlDb, _ := database.DB()
+		_ = sqlDb.Close()
+	})
+	return database
+}
+
+func createRole(ctx context.Context, database *gorm.DB, name string) error {
+	return Conn(ctx, database).Create(&models.Role{Name: name}).Error
+}
+
+func roleNames(t *testing.T, database *gorm.DB) []string {
+	t.Helper()
+	var names []string
+	require.NoError(t, database.Model(&models.Role{}).Order("name").Pluck("name", &names).Error)
+	return names
+}
+
+func TestWithTransactionCommits(t *testing.T) {
+	database := newTestDb(t)
+	uow := NewUnitOfWork(database)
+
+	err := uow.WithTransaction(context.Background(), func(ctx context.Context) error {
+		if err := createRole(ctx, database, "a"); err != nil {
+			return err
+		}
+		return createRole(ctx, database, "b")
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, roleNames(t, database))
+}
+
+func TestWithTransactionRollsBackOnError(t *testing.T) {
+	database := newTestDb(t)
+	failure := errors.New("boom")
+
+	err := WithTransaction(context.Background(), database, func(ctx context.Context) error {
+		require.NoError(t, createRole(ctx, database, "a"))
+		return failure
+	})
+	assert.ErrorIs(t, err, failure)
+	assert.Empty(t, roleNames(t, database))
+}
+
+func TestWithTransactionRollsBackOnPanic(t *testing.T) {
+	database := newTestDb(t)
+
+	assert.PanicsWithValue(t, "boom", func() {
+		_ = WithTransaction(context.Background(), database, func(ctx context.Context) error {
+			require.NoError(t, createRole(ctx, database, "a"))
+			panic("boom")
+		})
+	})
+	assert.Empty(t, roleNames(t, database))
+}
+
+func TestNestedTransactionUsesSavepoint(t *testing.T) {
+	database := newTestDb(t)
+
+	err := WithTransaction(context.Background(), database, func(ctx context.Context) error {
+		require.NoError(t, createRole(ctx, database, "outer"))
+		inner := WithTransaction(ctx, database, func(ctx context.Context) error {
+			require.NoError(t, createRole(ctx, database, "inner"))
+			return errors.New("inner failed")
+		})
+		assert.Error(t, inner)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"outer"}, roleNames(t, database))
+}
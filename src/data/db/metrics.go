@@ -0,0 +1,54 @@
+package db
+
+import (
+	"base_structure/src/pkg/metrics"
+	"errors"
+	"gorm.io/gorm"
+	"time"
+)
+
+const metricsStartKey = "metrics:start"
+
+// MetricsPlugin times every gorm statement into metrics.DbQueryDuration.
+type MetricsPlugin struct{}
+
+func (MetricsPlugin) Name() string {
+	return "metrics"
+}
+
+func (MetricsPlugin) Initialize(database *gorm.DB) error {
+	cb := database.Callback()
+	return errors.Join(
+		cb.Create().Before("gorm:create").Register("metrics:before_create", startTimer),
+		cb.Create().After("gorm:create").Register("metrics:after_create", observe("create")),
+		cb.Query().Before("gorm:query").Register("metrics:before_query", startTimer),
+		cb.Query().After("gorm:query").Register("metrics:after_query", observe("query")),
+		cb.Update().Before("gorm:update").Register("metrics:before_update", startTimer),
+		cb.Update().After("gorm:update").Register("metrics:after_update", observe("update")),
+		cb.Delete().Before("gorm:delete").Register("metrics:before_delete", startTimer),
+		cb.Delete().After("gorm:delete").Register("metrics:after_delete", observe("delete")),
+		cb.Row().Before("gorm:row").Register("metrics:before_row", startTimer),
+		cb.Row().After("gorm:row").Register("metrics:after_row", observe("row")),
+		cb.Raw().Before("gorm:raw").Register("metrics:before_raw", startTimer),
+		cb.Raw().After("gorm:raw").Register("metrics:after_raw", observe("raw")),
+	)
+}
+
+func startTimer(tx *gorm.DB) {
+	tx.InstanceSet(metricsStartKey, time.Now())
+}
+
+func observe(operation string) func(tx *gorm.DB) {
+	return func(tx *gorm.DB) {
+		v, ok := tx.InstanceGet(metricsStartKey)
+		if !ok {
+			return
+		}
+		start, _ := v.(time.Time)
+		result := metrics.Success
+		if tx.Error != nil && !errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+			result = metrics.Failure
+		}
+		metrics.DbQueryDuration.WithLabelValues(operation, tx.Statement.Table, result).Observe(time.Since(start).Seconds())
+	}
+}
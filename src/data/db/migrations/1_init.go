@@ -1,82 +1,62 @@
 package migrations
 
 import (
-	"base_structure/src/config"
-	"base_structure/src/constants"
-	"base_structure/src/data/db"
-	"base_structure/src/data/models"
-	"base_structure/src/pkg/logging"
-	"golang.org/x/crypto/bcrypt"
+	"database/sql"
 	"gorm.io/gorm"
 )
 
-var logger = logging.NewLogger(config.GetConfig())
-
-func Up1(cfg *config.Config) {
-	database := db.GetDb(cfg)
-	createTables(database)
-	createDefaultInformation(database)
+func init() {
+	register(Migration{Version: 1, Name: "init", Up: up1, Down: down1})
 }
 
-func Down1() {
-
+// The tables as they were when this migration was written. They are copies rather than the models, so later changes
+// to a model don't change what this migration creates.
+
+type user1 struct {
+	gorm.Model
+	CreatedBy    int            `gorm:"not null"`
+	UpdatedBy    *sql.NullInt64 `gorm:"null"`
+	DeletedBy    *sql.NullInt64 `gorm:"null"`
+	Username     string         `gorm:"type:string;size:20;not null;unique"`
+	FirstName    string         `gorm:"type:string;size:15;null"`
+	LastName     string         `gorm:"type:string;size:25;null"`
+	MobileNumber string         `gorm:"type:string;size:11;null;unique;default:null"`
+	Email        string         `gorm:"type:string;size:64;null;unique;default:null"`
+	Password     string         `gorm:"type:string;size:64;not null"`
+	Enabled      bool           `gorm:"default:true"`
+	RoleUsers    *[]roleUser1   `gorm:"foreignKey:UserId"`
 }
 
-func createTables(database *gorm.DB) {
-	var tables []interface{}
+func (user1) TableName() string { return "users" }
 
-	// User
-	tables = addNewTable(database, models.User{}, tables)
-	tables = addNewTable(database, models.Role{}, tables)
-	tables = addNewTable(database, models.RoleUser{}, tables)
-
-	err := database.Migrator().CreateTable(tables...)
-	if err != nil {
-		logger.Fatal(logging.Postgres, logging.Migration, "cannot create tables through migration", nil)
-		return
-	}
-	logger.Info(logging.Postgres, logging.Migration, "tables created", nil)
+type role1 struct {
+	gorm.Model
+	CreatedBy int            `gorm:"not null"`
+	UpdatedBy *sql.NullInt64 `gorm:"null"`
+	DeletedBy *sql.NullInt64 `gorm:"null"`
+	Name      string         `gorm:"type:string;size:30;not null;unique"`
+	RoleUsers *[]roleUser1   `gorm:"foreignKey:RoleId"`
 }
 
-func addNewTable(database *gorm.DB, model interface{}, tables []interface{}) []interface{} {
-	if !database.Migrator().HasTable(model) {
-		tables = append(tables, model)
-	}
-	return tables
+func (role1) TableName() string { return "roles" }
+
+type roleUser1 struct {
+	gorm.Model
+	CreatedBy int            `gorm:"not null"`
+	UpdatedBy *sql.NullInt64 `gorm:"null"`
+	DeletedBy *sql.NullInt64 `gorm:"null"`
+	Role      role1          `gorm:"foreignKey:RoleId;constraint:OnUpdate:NO ACTION;OnDelete:NO ACTION"`
+	User      user1          `gorm:"foreignKey:UserId;constraint:OnUpdate:NO ACTION;OnDelete:NO ACTION"`
+	RoleId    uint
+	UserId    uint
 }
 
-func createDefaultInformation(database *gorm.DB) {
-	adminRole := models.Role{Name: constants.AdminRoleName}
-	createRoleIfNotExists(database, &adminRole)
-	defaultRole := models.Role{Name: constants.DefaultRoleName}
-	createRoleIfNotExists(database, &defaultRole)
-	u := models.User{
-		Username:     constants.DefaultUserName,
-		FirstName:    constants.AdminFirstName,
-		LastName:     constants.AdminLastName,
-		MobileNumber: constants.AdminMobileNumber,
-		Email:        constants.AdminEmail,
-	}
-	pass := constants.AdminPassword
-	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte(pass), bcrypt.DefaultCost)
-	u.Password = string(hashedPassword)
-	createAdminUserIfNotExists(database, &u, adminRole.ID)
-}
+func (roleUser1) TableName() string { return "role_users" }
 
-func createRoleIfNotExists(database *gorm.DB, r *models.Role) {
-	exists := 0
-	database.Model(&models.Role{}).Select("1").Where("name = ?", r.Name).First(&exists)
-	if exists == 0 {
-		database.Create(r)
-	}
+func up1(tx *gorm.DB) error {
+	return createTables(tx, user1{}, role1{}, roleUser1{})
 }
 
-func createAdminUserIfNotExists(database *gorm.DB, u *models.User, roleId uint) {
-	exists := 0
-	database.Model(&models.User{}).Select("1").Where("username = ?", u.Username).First(&exists)
-	if exists == 0 {
-		database.Create(u)
-		ru := models.RoleUser{UserId: u.ID, RoleId: roleId}
-		database.Create(&ru)
-	}
+func down1(tx *gorm.DB) error {
+	return tx.Migrator().DropTable(roleUser1{}, user1{}, role1{})
 }
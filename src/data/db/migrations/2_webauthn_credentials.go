@@ -0,0 +1,47 @@
+package migrations
+
+import (
+	"database/sql"
+	"gorm.io/gorm"
+)
+
+func init() {
+	register(Migration{Version: 2, Name: "webauthn_credentials", Up: up2, Down: down2})
+}
+
+// The tables as they were when this migration was written, see 1_init.go.
+
+type user2 struct {
+	ID                  uint                   `gorm:"primarykey"`
+	WebAuthnCredentials *[]webAuthnCredential2 `gorm:"foreignKey:UserId"`
+}
+
+func (user2) TableName() string { return "users" }
+
+type webAuthnCredential2 struct {
+	gorm.Model
+	CreatedBy       int            `gorm:"not null"`
+	UpdatedBy       *sql.NullInt64 `gorm:"null"`
+	DeletedBy       *sql.NullInt64 `gorm:"null"`
+	User            user2          `gorm:"foreignKey:UserId;constraint:OnUpdate:NO ACTION;OnDelete:CASCADE"`
+	UserId          uint           `gorm:"not null;index"`
+	Name            string         `gorm:"type:string;size:64;null"`
+	CredentialId    []byte         `gorm:"not null;uniqueIndex"`
+	PublicKey       []byte         `gorm:"not null"`
+	AttestationType string         `gorm:"type:string;size:32;null"`
+	Transports      string         `gorm:"type:string;size:128;null"`
+	Aaguid          []byte         `gorm:"null"`
+	SignCount       uint32         `gorm:"not null;default:0"`
+	Flags           uint8          `gorm:"not null;default:0"`
+	CloneWarning    bool           `gorm:"default:false"`
+}
+
+func (webAuthnCredential2) TableName() string { return "web_authn_credentials" }
+
+func up2(tx *gorm.DB) error {
+	return createTables(tx, webAuthnCredential2{})
+}
+
+func down2(tx *gorm.DB) error {
+	return tx.Migrator().DropTable(webAuthnCredential2{})
+}
@@ -0,0 +1,42 @@
+package migrations
+
+import (
+	"database/sql"
+	"gorm.io/gorm"
+)
+
+func init() {
+	register(Migration{Version: 3, Name: "external_identities", Up: up3, Down: down3})
+}
+
+// The tables as they were when this migration was written, see 1_init.go.
+
+type user3 struct {
+	ID                 uint                 `gorm:"primarykey"`
+	ExternalIdentities *[]externalIdentity3 `gorm:"foreignKey:UserId"`
+}
+
+func (user3) TableName() string { return "users" }
+
+type externalIdentity3 struct {
+	gorm.Model
+	CreatedBy   int            `gorm:"not null"`
+	UpdatedBy   *sql.NullInt64 `gorm:"null"`
+	DeletedBy   *sql.NullInt64 `gorm:"null"`
+	User        user3          `gorm:"foreignKey:UserId;constraint:OnUpdate:NO ACTION;OnDelete:CASCADE"`
+	UserId      uint           `gorm:"not null;uniqueIndex:idx_external_identities_user_provider"`
+	Provider    string         `gorm:"type:string;size:32;not null;uniqueIndex:idx_external_identities_user_provider;uniqueIndex:idx_external_identities_provider_subject"`
+	Subject     string         `gorm:"type:string;size:255;not null;uniqueIndex:idx_external_identities_provider_subject"`
+	Email       string         `gorm:"type:string;size:64;null"`
+	Provisioned bool           `gorm:"default:false"`
+}
+
+func (externalIdentity3) TableName() string { return "external_identities" }
+
+func up3(tx *gorm.DB) error {
+	return createTables(tx, externalIdentity3{})
+}
+
+func down3(tx *gorm.DB) error {
+	return tx.Migrator().DropTable(externalIdentity3{})
+}
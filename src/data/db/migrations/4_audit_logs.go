@@ -0,0 +1,36 @@
+package migrations
+
+import (
+	"gorm.io/gorm"
+	"time"
+)
+
+func init() {
+	register(Migration{Version: 4, Name: "audit_logs", Up: up4, Down: down4})
+}
+
+// The table as it was when this migration was written, see 1_init.go.
+
+type auditLog4 struct {
+	ID         uint      `gorm:"primarykey"`
+	CreatedAt  time.Time `gorm:"index"`
+	ActorId    int       `gorm:"not null;index"`
+	Action     string    `gorm:"type:string;size:50;not null;index"`
+	TargetType string    `gorm:"type:string;size:50;null;index:idx_audit_logs_target"`
+	TargetId   string    `gorm:"type:string;size:64;null;index:idx_audit_logs_target"`
+	Ip         string    `gorm:"type:string;size:45;null"`
+	UserAgent  string    `gorm:"type:string;size:255;null"`
+	RequestId  string    `gorm:"type:string;size:64;null"`
+	Before     string    `gorm:"type:text;null"`
+	After      string    `gorm:"type:text;null"`
+}
+
+func (auditLog4) TableName() string { return "audit_logs" }
+
+func up4(tx *gorm.DB) error {
+	return createTables(tx, auditLog4{})
+}
+
+func down4(tx *gorm.DB) error {
+	return tx.Migrator().DropTable(auditLog4{})
+}
@@ -0,0 +1,27 @@
+package migrations
+
+import (
+	"base_structure/src/pkg/health"
+	"context"
+	"fmt"
+	"gorm.io/gorm"
+)
+
+// HealthChecker reports down while migrations are pending or the applied ones differ from this binary's, so an instance
+// doesn't take traffic on a schema it wasn't built for.
+func HealthChecker(database *gorm.DB) health.HealthChecker {
+	return health.NewChecker("migrations", func(ctx context.Context) error {
+		m := NewMigrator(database.WithContext(ctx))
+		applied, err := m.applied()
+		if err != nil {
+			return err
+		}
+		if err = m.verify(applied); err != nil {
+			return err
+		}
+		if pending := m.pending(applied); pending > 0 {
+			return fmt.Errorf("%d migrations pending", pending)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,142 @@
+package migrations
+
+import (
+	"base_structure/src/config"
+	"base_structure/src/pkg/logging"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"gorm.io/gorm"
+	"io"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"time"
+)
+
+var logger = logging.NewLogger(config.GetConfig())
+
+// sources holds the migration files themselves, so a checksum can detect edits to an already applied migration. Once a
+// migration is applied anywhere, change the schema with a new migration instead of editing it.
+//
+//go:embed [0-9]*_*.go
+var sources embed.FS
+
+type Migration struct {
+	Version  int
+	Name     string
+	Up       func(tx *gorm.DB) error
+	Down     func(tx *gorm.DB) error
+	checksum string
+}
+
+type schemaMigration struct {
+	Version   int    `gorm:"primaryKey;autoIncrement:false"`
+	Name      string `gorm:"type:string;size:255;not null"`
+	Checksum  string `gorm:"type:string;size:64;not null"`
+	AppliedAt time.Time
+}
+
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+var registry []*Migration
+
+// register adds a migration to the ordered registry. It is called from the init function of each numbered file.
+func register(m Migration) {
+	for _, r := range registry {
+		if r.Version == m.Version {
+			panic(fmt.Sprintf("migration version %d registered twice", m.Version))
+		}
+	}
+	_, file, _, _ := runtime.Caller(1)
+	sum, err := checksum(filepath.Base(file))
+	if err != nil {
+		panic(fmt.Sprintf("migration %d_%s: %v", m.Version, m.Name, err))
+	}
+	m.checksum = sum
+	registry = append(registry, &m)
+	sort.Slice(registry, func(i, j int) bool {
+		return registry[i].Version < registry[j].Version
+	})
+}
+
+// checksum hashes the syntax tree of a migration file rather than its text: comments, imports and formatting can
+// change without flagging the migration as modified, while any change to its code does.
+func checksum(file string) (string, error) {
+	content, err := sources.ReadFile(file)
+	if err != nil {
+		return "", fmt.Errorf("source not embedded: %w", err)
+	}
+	return sourceChecksum(file, content)
+}
+
+func sourceChecksum(file string, content []byte) (string, error) {
+	f, err := parser.ParseFile(token.NewFileSet(), file, content, parser.SkipObjectResolution)
+	if err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	for _, decl := range f.Decls {
+		if gd, ok := decl.(*ast.GenDecl); ok && gd.Tok == token.IMPORT {
+			continue
+		}
+		ast.Inspect(decl, func(n ast.Node) bool {
+			if n == nil {
+				_, _ = io.WriteString(h, ")")
+				return false
+			}
+			if _, ok := n.(*ast.CommentGroup); ok {
+				return false
+			}
+			_, _ = fmt.Fprintf(h, "(%T", n)
+			switch n := n.(type) {
+			case *ast.Ident:
+				_, _ = fmt.Fprintf(h, " %s", n.Name)
+			case *ast.BasicLit:
+				_, _ = fmt.Fprintf(h, " %s", n.Value)
+			case *ast.BinaryExpr:
+				_, _ = fmt.Fprintf(h, " %s", n.Op)
+			case *ast.UnaryExpr:
+				_, _ = fmt.Fprintf(h, " %s", n.Op)
+			case *ast.AssignStmt:
+				_, _ = fmt.Fprintf(h, " %s", n.Tok)
+			case *ast.RangeStmt:
+				_, _ = fmt.Fprintf(h, " %s", n.Tok)
+			case *ast.IncDecStmt:
+				_, _ = fmt.Fprintf(h, " %s", n.Tok)
+			case *ast.BranchStmt:
+				_, _ = fmt.Fprintf(h, " %s", n.Tok)
+			case *ast.GenDecl:
+				_, _ = fmt.Fprintf(h, " %s", n.Tok)
+			}
+			return true
+		})
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// All returns the registered migrations in version order.
+func All() []*Migration {
+	return registry
+}
+
+// createTables creates the tables of the given models that don't exist yet, so a migration can be applied on top of a
+// database that was created before migrations were versioned.
+func createTables(tx *gorm.DB, models ...interface{}) error {
+	var tables []interface{}
+	for _, model := range models {
+		if !tx.Migrator().HasTable(model) {
+			tables = append(tables, model)
+		}
+	}
+	if len(tables) == 0 {
+		return nil
+	}
+	return tx.Migrator().CreateTable(tables...)
+}
@@ -0,0 +1,157 @@
+package migrations
+
+import (
+	"base_structure/src/data/models"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"strings"
+	"testing"
+)
+
+func newTestDb(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())
+	database, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		sqlDb, _ := database.DB()
+		_ = sqlDb.Close()
+	})
+	return database
+}
+
+func TestMigratorUpAndDown(t *testing.T) {
+	database := newTestDb(t)
+	m := NewMigrator(database)
+
+	require.NoError(t, m.Up())
+	pending, err := m.Pending()
+	require.NoError(t, err)
+	assert.Zero(t, pending)
+	assert.True(t, database.Migrator().HasTable(&models.User{}))
+	assert.True(t, database.Migrator().HasTable(&models.ExternalIdentity{}))
+
+	// Running again is a no-op.
+	require.NoError(t, m.Up())
+
+	require.NoError(t, m.Down())
+	pending, err = m.Pending()
+	require.NoError(t, err)
+	assert.Equal(t, 1, pending)
+	statuses, err := m.Status()
+	require.NoError(t, err)
+	assert.False(t, statuses[len(statuses)-1].Applied)
+	assert.True(t, statuses[len(statuses)-2].Applied)
+	assert.True(t, database.Migrator().HasTable(&models.User{}))
+}
+
+func TestMigratorTo(t *testing.T) {
+	database := newTestDb(t)
+	m := NewMigrator(database)
+
+	require.NoError(t, m.To(1))
+	statuses, err := m.Status()
+	require.NoError(t, err)
+	require.Len(t, statuses, len(All()))
+	assert.True(t, statuses[0].Applied)
+	assert.False(t, statuses[1].Applied)
+
+	require.NoError(t, m.Up())
+	require.NoError(t, m.To(0))
+	assert.False(t, database.Migrator().HasTable(&models.User{}))
+	pending, err := m.Pending()
+	require.NoError(t, err)
+	assert.Equal(t, len(All()), pending)
+
+	assert.True(t, errors.Is(m.To(999), ErrUnknownVersion))
+}
+
+func TestMigratorDetectsModifiedMigration(t *testing.T) {
+	database := newTestDb(t)
+	m := NewMigrator(database)
+	require.NoError(t, m.Up())
+
+	require.NoError(t, database.Model(&schemaMigration{}).Where("version = ?", 1).Update("checksum", "edited").Error)
+	assert.True(t, errors.Is(m.Up(), ErrChecksumMismatch))
+
+	assert.True(t, errors.Is(m.Down(), ErrChecksumMismatch))
+	assert.True(t, database.Migrator().HasTable(&models.AuditLog{}), "down must not revert past a modified migration")
+
+	statuses, err := m.Status()
+	require.NoError(t, err)
+	assert.True(t, statuses[0].ChecksumMismatch)
+}
+
+func TestChecksumIgnoresCommentsImportsAndFormatting(t *testing.T) {
+	original := "package migrations\n\nimport \"gorm.io/gorm\"\n\n" +
+		"type user9 struct {\n\tName string `gorm:\"size:20\"`\n}\n\n" +
+		"func up9(tx *gorm.DB) error { return createTables(tx, user9{}) }\n"
+	cosmetic := "package migrations\n\nimport (\n\t\"fmt\"\n\t\"gorm.io/gorm\"\n)\n\n" +
+		"// user9 is the users table.\ntype user9 struct {\n\tName   string `gorm:\"size:20\"` // the login\n}\n\n" +
+		"func up9(tx *gorm.DB) error {\n\treturn createTables(tx, user9{})\n}\n"
+	edited := strings.Replace(original, "size:20", "size:30", 1)
+
+	sum, err := sourceChecksum("9_users.go", []byte(original))
+	require.NoError(t, err)
+	same, err := sourceChecksum("9_users.go", []byte(cosmetic))
+	require.NoError(t, err)
+	changed, err := sourceChecksum("9_users.go", []byte(edited))
+	require.NoError(t, err)
+	assert.Equal(t, sum, same)
+	assert.NotEqual(t, sum, changed)
+
+	_, err = checksum("9_missing.go")
+	assert.Error(t, err)
+}
+
+// The migrations create frozen copies of the tables, so a model column without a migration adding it shows up here.
+func TestMigrationsCoverModels(t *testing.T) {
+	database := newTestDb(t)
+	require.NoError(t, NewMigrator(database).Up())
+
+	for _, model := range []interface{}{
+		&models.User{},
+		&models.Role{},
+		&models.RoleUser{},
+		&models.WebAuthnCredential{},
+		&models.ExternalIdentity{},
+		&models.AuditLog{},
+	} {
+		stmt := &gorm.Statement{DB: database}
+		require.NoError(t, stmt.Parse(model))
+		for _, field := range stmt.Schema.Fields {
+			if field.DBName != "" {
+				assert.True(t, database.Migrator().HasColumn(model, field.DBName), "%s.%s", stmt.Schema.Table, field.DBName)
+			}
+		}
+	}
+}
+
+func TestMigratorRejectsUnknownAppliedVersion(t *testing.T) {
+	database := newTestDb(t)
+	m := NewMigrator(database)
+	require.NoError(t, m.Up())
+
+	require.NoError(t, database.Create(&schemaMigration{Version: 999, Name: "future", Checksum: "x"}).Error)
+	assert.True(t, errors.Is(m.Up(), ErrUnknownVersion))
+}
+
+func TestHealthCheckerReportsPendingMigrations(t *testing.T) {
+	database := newTestDb(t)
+	checker := HealthChecker(database)
+
+	err := checker.Check(context.Background())
+	assert.ErrorContains(t, err, "migrations pending")
+	assert.False(t, database.Migrator().HasTable(&schemaMigration{}), "the probe must not create tables")
+
+	require.NoError(t, NewMigrator(database).Up())
+	assert.NoError(t, checker.Check(context.Background()))
+
+	require.NoError(t, database.Model(&schemaMigration{}).Where("version = ?", 1).Update("checksum", "edited").Error)
+	assert.ErrorIs(t, checker.Check(context.Background()), ErrChecksumMismatch)
+}
@@ -0,0 +1,207 @@
+package migrations
+
+import (
+	"base_structure/src/pkg/logging"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"time"
+)
+
+var (
+	ErrChecksumMismatch = errors.New("applied migration has been modified")
+	ErrUnknownVersion   = errors.New("unknown migration version")
+	ErrIrreversible     = errors.New("migration has no down step")
+)
+
+type Status struct {
+	Version          int
+	Name             string
+	Applied          bool
+	AppliedAt        *time.Time
+	ChecksumMismatch bool
+}
+
+// Migrator applies and reverts the registered migrations, tracking them in the schema_migrations table.
+type Migrator struct {
+	database   *gorm.DB
+	migrations []*Migration
+}
+
+func NewMigrator(database *gorm.DB) *Migrator {
+	return &Migrator{database: database, migrations: All()}
+}
+
+// Up applies every pending migration.
+func (m *Migrator) Up() error {
+	if len(m.migrations) == 0 {
+		return nil
+	}
+	return m.To(m.migrations[len(m.migrations)-1].Version)
+}
+
+// Down reverts the most recently applied migration.
+func (m *Migrator) Down() error {
+	applied, err := m.applied()
+	if err != nil {
+		return err
+	}
+	if err = m.verify(applied); err != nil {
+		return err
+	}
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		if _, ok := applied[m.migrations[i].Version]; ok {
+			return m.revert(m.migrations[i])
+		}
+	}
+	return nil
+}
+
+// To migrates up or down until exactly the migrations up to version are applied. Version 0 reverts everything.
+func (m *Migrator) To(version int) error {
+	if version != 0 && m.find(version) == nil {
+		return fmt.Errorf("%w: %d", ErrUnknownVersion, version)
+	}
+	if err := m.database.AutoMigrate(&schemaMigration{}); err != nil {
+		return err
+	}
+	applied, err := m.applied()
+	if err != nil {
+		return err
+	}
+	if err = m.verify(applied); err != nil {
+		return err
+	}
+	for _, mig := range m.migrations {
+		if _, ok := applied[mig.Version]; !ok && mig.Version <= version {
+			if err = m.apply(mig); err != nil {
+				return err
+			}
+		}
+	}
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		mig := m.migrations[i]
+		if _, ok := applied[mig.Version]; ok && mig.Version > version {
+			if err = m.revert(mig); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (m *Migrator) Status() ([]Status, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, mig := range m.migrations {
+		st := Status{Version: mig.Version, Name: mig.Name}
+		if row, ok := applied[mig.Version]; ok {
+			appliedAt := row.AppliedAt
+			st.Applied = true
+			st.AppliedAt = &appliedAt
+			st.ChecksumMismatch = row.Checksum != mig.checksum
+		}
+		statuses = append(statuses, st)
+	}
+	return statuses, nil
+}
+
+// Pending returns the number of registered migrations that are not applied yet.
+func (m *Migrator) Pending() (int, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return 0, err
+	}
+	return m.pending(applied), nil
+}
+
+func (m *Migrator) pending(applied map[int]schemaMigration) int {
+	pending := 0
+	for _, mig := range m.migrations {
+		if _, ok := applied[mig.Version]; !ok {
+			pending++
+		}
+	}
+	return pending
+}
+
+func (m *Migrator) apply(mig *Migration) error {
+	err := m.database.Transaction(func(tx *gorm.DB) error {
+		if err := mig.Up(tx); err != nil {
+			return err
+		}
+		return tx.Create(&schemaMigration{
+			Version:   mig.Version,
+			Name:      mig.Name,
+			Checksum:  mig.checksum,
+			AppliedAt: time.Now(),
+		}).Error
+	})
+	if err != nil {
+		logger.Error(logging.Postgres, logging.Migration, fmt.Sprintf("migration %d_%s failed: %v", mig.Version, mig.Name, err), nil)
+		return fmt.Errorf("migration %d_%s up: %w", mig.Version, mig.Name, err)
+	}
+	logger.Info(logging.Postgres, logging.Migration, fmt.Sprintf("migration %d_%s applied", mig.Version, mig.Name), nil)
+	return nil
+}
+
+func (m *Migrator) revert(mig *Migration) error {
+	if mig.Down == nil {
+		return fmt.Errorf("%w: %d_%s", ErrIrreversible, mig.Version, mig.Name)
+	}
+	err := m.database.Transaction(func(tx *gorm.DB) error {
+		if err := mig.Down(tx); err != nil {
+			return err
+		}
+		return tx.Delete(&schemaMigration{}, mig.Version).Error
+	})
+	if err != nil {
+		logger.Error(logging.Postgres, logging.Migration, fmt.Sprintf("migration %d_%s revert failed: %v", mig.Version, mig.Name, err), nil)
+		return fmt.Errorf("migration %d_%s down: %w", mig.Version, mig.Name, err)
+	}
+	logger.Info(logging.Postgres, logging.Migration, fmt.Sprintf("migration %d_%s reverted", mig.Version, mig.Name), nil)
+	return nil
+}
+
+// applied reads the schema_migrations table without creating it, so Status and Pending are cheap enough for a
+// readiness probe. To creates the table before applying anything.
+func (m *Migrator) applied() (map[int]schemaMigration, error) {
+	if !m.database.Migrator().HasTable(&schemaMigration{}) {
+		return map[int]schemaMigration{}, nil
+	}
+	var rows []schemaMigration
+	if err := m.database.Order("version").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	applied := make(map[int]schemaMigration, len(rows))
+	for _, row := range rows {
+		applied[row.Version] = row
+	}
+	return applied, nil
+}
+
+// verify refuses to run when the database contains migrations this binary doesn't know or has edited since.
+func (m *Migrator) verify(applied map[int]schemaMigration) error {
+	for version, row := range applied {
+		mig := m.find(version)
+		if mig == nil {
+			return fmt.Errorf("%w: %d is applied but not registered", ErrUnknownVersion, version)
+		}
+		if row.Checksum != mig.checksum {
+			return fmt.Errorf("%w: %d_%s", ErrChecksumMismatch, mig.Version, mig.Name)
+		}
+	}
+	return nil
+}
+
+func (m *Migrator) find(version int) *Migration {
+	for _, mig := range m.migrations {
+		if mig.Version == version {
+			return mig
+		}
+	}
+	return nil
+}
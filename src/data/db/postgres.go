@@ -2,8 +2,12 @@ package db
 
 import (
 	"base_structure/src/config"
+	"base_structure/src/pkg/health"
 	"base_structure/src/pkg/logging"
+	"base_structure/src/pkg/metrics"
+	"context"
 	"fmt"
+	"github.com/prometheus/client_golang/prometheus/collectors"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"sync"
@@ -38,6 +42,15 @@ func InitDb(cfg *config.Config) error {
 		sqlDb.SetMaxIdleConns(cfg.Postgres.MaxIdleConnections)
 		sqlDb.SetMaxOpenConns(cfg.Postgres.MaxOpenConnections)
 		sqlDb.SetConnMaxLifetime(cfg.Postgres.ConnectionMaxLifetime * time.Minute)
+		err = dbClient.Use(MetricsPlugin{})
+		if err != nil {
+			return
+		}
+		err = dbClient.Use(TracingPlugin{})
+		if err != nil {
+			return
+		}
+		metrics.Registry.MustRegister(collectors.NewDBStatsCollector(sqlDb, cfg.Postgres.DbName))
 		logger.Info(logging.Postgres, logging.StartUp, "db connection established", nil)
 	})
 	return err
@@ -53,14 +66,25 @@ func GetDb(cfg *config.Config) *gorm.DB {
 	return dbClient
 }
 
-func CloseDb() {
+func CloseDb() error {
 	if dbClient != nil {
 		cnn, _ := dbClient.DB()
 		err := cnn.Close()
 		if err != nil {
-			logger.Info(logging.Postgres, logging.Closing, "error on closing db connection", nil)
-			return
+			logger.Error(logging.Postgres, logging.Closing, "error on closing db connection", nil)
+			return err
 		}
 		dbClient = nil
 	}
+	return nil
+}
+
+func HealthChecker(database *gorm.DB) health.HealthChecker {
+	return health.NewChecker("postgres", func(ctx context.Context) error {
+		sqlDb, err := database.DB()
+		if err != nil {
+			return err
+		}
+		return sqlDb.PingContext(ctx)
+	})
 }
@@ -0,0 +1,36 @@
+package seeders
+
+import (
+	"base_structure/src/data/models"
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+var (
+	firstNames = []string{"Ali", "Sara", "Reza", "Maryam", "Hossein", "Zahra", "Mehdi", "Fatemeh", "Amir", "Narges"}
+	lastNames  = []string{"Ahmadi", "Hosseini", "Karimi", "Rezaei", "Moradi", "Mohammadi", "Jafari", "Sadeghi"}
+)
+
+// generator produces plausible user data for development databases.
+type generator struct {
+	rnd *rand.Rand
+}
+
+func newGenerator(seed int64) *generator {
+	return &generator{rnd: rand.New(rand.NewSource(seed))}
+}
+
+// user returns the n-th fake user. Usernames depend only on n, so they stay unique and stable.
+func (g *generator) user(n int) models.User {
+	first := firstNames[g.rnd.Intn(len(firstNames))]
+	last := lastNames[g.rnd.Intn(len(lastNames))]
+	username := fmt.Sprintf("fake_user_%03d", n)
+	return models.User{
+		Username:     username,
+		FirstName:    first,
+		LastName:     last,
+		MobileNumber: fmt.Sprintf("09%09d", 100000000+n),
+		Email:        fmt.Sprintf("%s.%s.%d@example.com", strings.ToLower(first), strings.ToLower(last), n),
+	}
+}
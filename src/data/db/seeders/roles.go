@@ -0,0 +1,31 @@
+package seeders
+
+import (
+	"base_structure/src/constants"
+	"base_structure/src/data/models"
+	"errors"
+	"gorm.io/gorm"
+)
+
+var rolesSeeder = Seeder{Name: "roles", Run: seedRoles}
+
+func seedRoles(tx *gorm.DB) error {
+	if constants.AdminRoleName == "" || constants.DefaultRoleName == "" {
+		return errors.New("ADMIN_ROLE_NAME and DEFAULT_ROLE_NAME must be set")
+	}
+	for _, name := range []string{constants.AdminRoleName, constants.DefaultRoleName} {
+		if _, err := firstOrCreateRole(tx, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func firstOrCreateRole(tx *gorm.DB, name string) (*models.Role, error) {
+	var role models.Role
+	err := tx.Where(models.Role{Name: name}).FirstOrCreate(&role).Error
+	if err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
@@ -0,0 +1,62 @@
+package seeders
+
+import (
+	"base_structure/src/config"
+	"base_structure/src/pkg/actor"
+	"base_structure/src/pkg/logging"
+	"context"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"sort"
+)
+
+var logger = logging.NewLogger(config.GetConfig())
+
+var ErrUnknownProfile = errors.New("unknown seed profile")
+
+// Seeder inserts data the application or a developer needs. Run must be idempotent: seeding twice leaves the
+// database as seeding once did.
+type Seeder struct {
+	Name string
+	Run  func(tx *gorm.DB) error
+}
+
+const (
+	Production  = "production"
+	Development = "development"
+)
+
+var profiles = map[string][]Seeder{
+	Production:  {rolesSeeder},
+	Development: {rolesSeeder, adminSeeder, fakeUsersSeeder},
+}
+
+// Profiles returns the names of the available profiles.
+func Profiles() []string {
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Seed runs the seeders of profile in order, each in its own transaction, and stops at the first failure. Rows are
+// attributed to the system actor.
+func Seed(ctx context.Context, database *gorm.DB, profile string) error {
+	seeders, ok := profiles[profile]
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownProfile, profile)
+	}
+	database = database.WithContext(actor.WithSystem(ctx))
+	for _, s := range seeders {
+		err := database.Transaction(s.Run)
+		if err != nil {
+			logger.Error(logging.Postgres, logging.Seed, fmt.Sprintf("seeder %s failed: %v", s.Name, err), nil)
+			return fmt.Errorf("seeder %s: %w", s.Name, err)
+		}
+		logger.Info(logging.Postgres, logging.Seed, fmt.Sprintf("seeder %s done", s.Name), nil)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package seeders
+
+import (
+	"base_structure/src/constants"
+	"base_structure/src/data/models"
+	"base_structure/src/pkg/actor"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"testing"
+)
+
+func newTestDb(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())
+	database, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, database.AutoMigrate(&models.User{}, &models.Role{}, &models.RoleUser{}))
+	t.Cleanup(func() {
+		sqlDb, _ := database.DB()
+		_ = sqlDb.Close()
+	})
+	return database
+}
+
+func setTestConstants(t *testing.T) {
+	t.Helper()
+	constants.AdminRoleName = "admin"
+	constants.DefaultRoleName = "default"
+	constants.DefaultUserName = "admin"
+	constants.AdminPassword = "admin-password"
+	t.Cleanup(func() {
+		constants.AdminRoleName = ""
+		constants.DefaultRoleName = ""
+		constants.DefaultUserName = ""
+		constants.AdminPassword = ""
+	})
+}
+
+func count(t *testing.T, database *gorm.DB, model interface{}) int64 {
+	t.Helper()
+	var n int64
+	require.NoError(t, database.Model(model).Count(&n).Error)
+	return n
+}
+
+func TestSeedProductionCreatesRolesOnly(t *testing.T) {
+	setTestConstants(t)
+	database := newTestDb(t)
+
+	require.NoError(t, Seed(context.Background(), database, Production))
+	assert.Equal(t, int64(2), count(t, database, &models.Role{}))
+	assert.Zero(t, count(t, database, &models.User{}))
+}
+
+func TestSeedDevelopmentIsIdempotent(t *testing.T) {
+	setTestConstants(t)
+	database := newTestDb(t)
+
+	require.NoError(t, Seed(context.Background(), database, Development))
+	require.NoError(t, Seed(context.Background(), database, Development))
+	assert.Equal(t, int64(2), count(t, database, &models.Role{}))
+	assert.Equal(t, int64(fakeUsersCount+1), count(t, database, &models.User{}))
+	assert.Equal(t, int64(fakeUsersCount+1), count(t, database, &models.RoleUser{}))
+
+	var admin models.User
+	require.NoError(t, database.Where("username = ?", "admin").Preload("RoleUsers.Role").First(&admin).Error)
+	assert.Equal(t, actor.System, admin.CreatedBy)
+	require.Len(t, *admin.RoleUsers, 1)
+	assert.Equal(t, "admin", (*admin.RoleUsers)[0].Role.Name)
+}
+
+func TestSeedReportsErrors(t *testing.T) {
+	database := newTestDb(t)
+
+	err := Seed(context.Background(), database, Production)
+	assert.Error(t, err)
+	assert.Zero(t, count(t, database, &models.Role{}))
+
+	assert.True(t, errors.Is(Seed(context.Background(), database, "staging"), ErrUnknownProfile))
+}
@@ -0,0 +1,71 @@
+package seeders
+
+import (
+	"base_structure/src/constants"
+	"base_structure/src/data/models"
+	"errors"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+const (
+	fakeUsersCount   = 20
+	fakeUserPassword = "Passw0rd!"
+	// fakeDataSeed keeps generated users stable between runs, so reseeding finds the same usernames.
+	fakeDataSeed = 1
+)
+
+var (
+	adminSeeder     = Seeder{Name: "admin", Run: seedAdmin}
+	fakeUsersSeeder = Seeder{Name: "fake_users", Run: seedFakeUsers}
+)
+
+func seedAdmin(tx *gorm.DB) error {
+	if constants.DefaultUserName == "" || constants.AdminPassword == "" {
+		return errors.New("DEFAULT_USER_NAME and ADMIN_PASSWORD must be set")
+	}
+	role, err := firstOrCreateRole(tx, constants.AdminRoleName)
+	if err != nil {
+		return err
+	}
+	return createUserIfNotExists(tx, &models.User{
+		Username:     constants.DefaultUserName,
+		FirstName:    constants.AdminFirstName,
+		LastName:     constants.AdminLastName,
+		MobileNumber: constants.AdminMobileNumber,
+		Email:        constants.AdminEmail,
+	}, constants.AdminPassword, role.ID)
+}
+
+func seedFakeUsers(tx *gorm.DB) error {
+	role, err := firstOrCreateRole(tx, constants.DefaultRoleName)
+	if err != nil {
+		return err
+	}
+	gen := newGenerator(fakeDataSeed)
+	for i := 1; i <= fakeUsersCount; i++ {
+		u := gen.user(i)
+		if err = createUserIfNotExists(tx, &u, fakeUserPassword, role.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createUserIfNotExists creates u with the given role unless a user with the same username already exists.
+func createUserIfNotExists(tx *gorm.DB, u *models.User, password string, roleId uint) error {
+	var count int64
+	err := tx.Model(&models.User{}).Where("username = ?", u.Username).Count(&count).Error
+	if err != nil || count > 0 {
+		return err
+	}
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	u.Password = string(hashedPassword)
+	if err = tx.Create(u).Error; err != nil {
+		return err
+	}
+	return tx.Create(&models.RoleUser{UserId: u.ID, RoleId: roleId}).Error
+}
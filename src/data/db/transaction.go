@@ -0,0 +1,51 @@
+package db
+
+import (
+	"base_structure/src/pkg/logging"
+	"context"
+	"fmt"
+	"gorm.io/gorm"
+)
+
+type txKey struct{}
+
+// UnitOfWork groups repository calls into one atomic operation.
+type UnitOfWork interface {
+	// WithTransaction runs fn in a transaction that travels in the ctx fn receives. It commits when fn returns nil and
+	// rolls back when fn returns an error or panics, in which case the panic carries on to the caller. Calling it again
+	// with that ctx opens a savepoint.
+	WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type unitOfWork struct {
+	database *gorm.DB
+}
+
+func NewUnitOfWork(database *gorm.DB) UnitOfWork {
+	return &unitOfWork{database: database}
+}
+
+func (u *unitOfWork) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return WithTransaction(ctx, u.database, fn)
+}
+
+func WithTransaction(ctx context.Context, database *gorm.DB, fn func(ctx context.Context) error) error {
+	return Conn(ctx, database).Transaction(func(tx *gorm.DB) error {
+		defer func() {
+			if r := recover(); r != nil {
+				logger.WithContext(ctx).Error(logging.Postgres, logging.Rollback, fmt.Sprintf("panic inside transaction: %v", r), nil)
+				panic(r)
+			}
+		}()
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
+
+// Conn returns the transaction carried by ctx, or database bound to ctx when there is none. Repositories use it for
+// every statement, so they join the caller's transaction without knowing about it.
+func Conn(ctx context.Context, database *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx
+	}
+	return database.WithContext(ctx)
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// AuditLog is an append-only record of a security-relevant event. It doesn't embed BaseModel: rows are never
+// updated or deleted through the application.
+type AuditLog struct {
+	ID         uint      `gorm:"primarykey"`
+	CreatedAt  time.Time `gorm:"index"`
+	ActorId    int       `gorm:"not null;index"`
+	Action     string    `gorm:"type:string;size:50;not null;index"`
+	TargetType string    `gorm:"type:string;size:50;null;index:idx_audit_logs_target"`
+	TargetId   string    `gorm:"type:string;size:64;null;index:idx_audit_logs_target"`
+	Ip         string    `gorm:"type:string;size:45;null"`
+	UserAgent  string    `gorm:"type:string;size:255;null"`
+	RequestId  string    `gorm:"type:string;size:64;null"`
+	Before     string    `gorm:"type:text;null"`
+	After      string    `gorm:"type:text;null"`
+}
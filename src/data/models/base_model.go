@@ -1,7 +1,7 @@
 package models
 
 import (
-	"base_structure/src/constants"
+	"base_structure/src/pkg/actor"
 	"database/sql"
 	"gorm.io/gorm"
 )
@@ -14,37 +14,23 @@ type BaseModel struct {
 }
 
 func (m *BaseModel) BeforeCreate(tx *gorm.DB) (err error) {
-	value := tx.Statement.Context.Value(constants.UserIdKey)
-	var userId = -1
-	if value != nil {
-		userId = int(value.(float64))
-	}
-	m.CreatedBy = userId
+	m.CreatedBy, _ = actor.FromContext(tx.Statement.Context)
 	return
 }
 
+// BeforeUpdate sets updated_by through the statement, so updates given a map of columns write it too.
 func (m *BaseModel) BeforeUpdate(tx *gorm.DB) (err error) {
-	value := tx.Statement.Context.Value(constants.UserIdKey)
-	var userId = &sql.NullInt64{Valid: false}
-	if value != nil {
-		userId = &sql.NullInt64{
-			Valid: true,
-			Int64: int64(value.(float64)),
-		}
-	}
-	m.UpdatedBy = userId
+	tx.Statement.SetColumn("updated_by", actorOf(tx))
 	return
 }
 
-func (m *BaseModel) BeforeDelete(tx *gorm.DB) (err error) {
-	value := tx.Statement.Context.Value(constants.UserIdKey)
-	var userId = &sql.NullInt64{Valid: false}
-	if value != nil {
-		userId = &sql.NullInt64{
-			Valid: true,
-			Int64: int64(value.(float64)),
-		}
-	}
-	m.DeletedBy = userId
-	return
+// DeletedColumns are the columns a soft delete writes. gorm's own soft delete only sets deleted_at, so the repository
+// updates these in its place.
+func DeletedColumns(tx *gorm.DB) map[string]interface{} {
+	return map[string]interface{}{"deleted_at": tx.NowFunc(), "deleted_by": actorOf(tx)}
+}
+
+func actorOf(tx *gorm.DB) *sql.NullInt64 {
+	id, ok := actor.FromContext(tx.Statement.Context)
+	return &sql.NullInt64{Valid: ok, Int64: int64(id)}
 }
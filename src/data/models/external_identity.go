@@ -0,0 +1,12 @@
+package models
+
+type ExternalIdentity struct {
+	BaseModel
+	User     User   `gorm:"foreignKey:UserId;constraint:OnUpdate:NO ACTION;OnDelete:CASCADE"`
+	UserId   uint   `gorm:"not null;uniqueIndex:idx_external_identities_user_provider"`
+	Provider string `gorm:"type:string;size:32;not null;uniqueIndex:idx_external_identities_user_provider;uniqueIndex:idx_external_identities_provider_subject"`
+	Subject  string `gorm:"type:string;size:255;not null;uniqueIndex:idx_external_identities_provider_subject"`
+	Email    string `gorm:"type:string;size:64;null"`
+	// Provisioned marks the identity the user account was created from.
+	Provisioned bool `gorm:"default:false"`
+}
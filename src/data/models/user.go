@@ -10,6 +10,9 @@ type User struct {
 	Password     string `gorm:"type:string;size:64;not null"`
 	Enabled      bool   `gorm:"default:true"`
 	RoleUsers    *[]RoleUser
+
+	WebAuthnCredentials *[]WebAuthnCredential
+	ExternalIdentities  *[]ExternalIdentity
 }
 
 type Role struct {
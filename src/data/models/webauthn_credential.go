@@ -0,0 +1,16 @@
+package models
+
+type WebAuthnCredential struct {
+	BaseModel
+	User            User   `gorm:"foreignKey:UserId;constraint:OnUpdate:NO ACTION;OnDelete:CASCADE"`
+	UserId          uint   `gorm:"not null;index"`
+	Name            string `gorm:"type:string;size:64;null"`
+	CredentialId    []byte `gorm:"not null;uniqueIndex"`
+	PublicKey       []byte `gorm:"not null"`
+	AttestationType string `gorm:"type:string;size:32;null"`
+	Transports      string `gorm:"type:string;size:128;null"`
+	Aaguid          []byte `gorm:"null"`
+	SignCount       uint32 `gorm:"not null;default:0"`
+	Flags           uint8  `gorm:"not null;default:0"`
+	CloneWarning    bool   `gorm:"default:false"`
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// QueryOption narrows or shapes a query. Options are applied in the order they are given.
+type QueryOption func(*gorm.DB) *gorm.DB
+
+// Where adds a raw condition, e.g. Where("created_at > ?", t).
+func Where(query interface{}, args ...interface{}) QueryOption {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(query, args...)
+	}
+}
+
+// Filter adds an equality condition on column. The column name is quoted, the value is bound.
+func Filter(column string, value interface{}) QueryOption {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(clause.Eq{Column: clause.Column{Name: column}, Value: value})
+	}
+}
+
+// Preload loads an association, nested ones written with dots ("RoleUsers.Role").
+func Preload(association string, args ...interface{}) QueryOption {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Preload(association, args...)
+	}
+}
+
+// Select limits the loaded columns.
+func Select(columns ...string) QueryOption {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Select(columns)
+	}
+}
+
+// Sort orders by column, descending when desc is set.
+func Sort(column string, desc bool) QueryOption {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Order(clause.OrderByColumn{Column: clause.Column{Name: column}, Desc: desc})
+	}
+}
+
+// Paginate returns page (starting at 1) of the given size. Out of range values fall back to the first page and
+// DefaultPageSize.
+func Paginate(page, size int) QueryOption {
+	return NewPage(page, size).Option()
+}
+
+// Page is a normalized page request.
+type Page struct {
+	Number int
+	Size   int
+}
+
+func NewPage(number, size int) Page {
+	if number < 1 {
+		number = 1
+	}
+	if size < 1 || size > MaxPageSize {
+		size = DefaultPageSize
+	}
+	return Page{Number: number, Size: size}
+}
+
+func (p Page) Option() QueryOption {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Offset((p.Number - 1) * p.Size).Limit(p.Size)
+	}
+}
+
+func (p Page) TotalPages(total int64) int {
+	return int((total + int64(p.Size) - 1) / int64(p.Size))
+}
+
+const (
+	DefaultPageSize = 10
+	MaxPageSize     = 100
+)
+
+func apply(db *gorm.DB, opts []QueryOption) *gorm.DB {
+	for _, opt := range opts {
+		db = opt(db)
+	}
+	return db
+}
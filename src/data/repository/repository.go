@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"base_structure/src/data/db"
+	"base_structure/src/data/models"
+	"context"
+	"gorm.io/gorm"
+)
+
+// Repository is the data access contract shared by all models. Lookups return gorm.ErrRecordNotFound when nothing
+// matches. Every call runs in the transaction carried by ctx, if any (see db.WithTransaction).
+type Repository[T any] interface {
+	FindByID(ctx context.Context, id uint, opts ...QueryOption) (*T, error)
+	FindOne(ctx context.Context, opts ...QueryOption) (*T, error)
+	List(ctx context.Context, opts ...QueryOption) ([]T, error)
+	Count(ctx context.Context, opts ...QueryOption) (int64, error)
+	Exists(ctx context.Context, opts ...QueryOption) (bool, error)
+	Create(ctx context.Context, entity *T) error
+	Update(ctx context.Context, entity *T) error
+	SoftDelete(ctx context.Context, id uint) error
+}
+
+type GormRepository[T any] struct {
+	database *gorm.DB
+}
+
+func NewRepository[T any](database *gorm.DB) *GormRepository[T] {
+	return &GormRepository[T]{database: database}
+}
+
+func (r *GormRepository[T]) conn(ctx context.Context) *gorm.DB {
+	return db.Conn(ctx, r.database)
+}
+
+func (r *GormRepository[T]) query(ctx context.Context, opts []QueryOption) *gorm.DB {
+	return apply(r.conn(ctx).Model(new(T)), opts)
+}
+
+func (r *GormRepository[T]) FindByID(ctx context.Context, id uint, opts ...QueryOption) (*T, error) {
+	var entity T
+	err := r.query(ctx, opts).Where("id = ?", id).First(&entity).Error
+	if err != nil {
+		return nil, err
+	}
+	return &entity, nil
+}
+
+func (r *GormRepository[T]) FindOne(ctx context.Context, opts ...QueryOption) (*T, error) {
+	var entity T
+	err := r.query(ctx, opts).First(&entity).Error
+	if err != nil {
+		return nil, err
+	}
+	return &entity, nil
+}
+
+func (r *GormRepository[T]) List(ctx context.Context, opts ...QueryOption) ([]T, error) {
+	var entities []T
+	err := r.query(ctx, opts).Find(&entities).Error
+	if err != nil {
+		return nil, err
+	}
+	return entities, nil
+}
+
+func (r *GormRepository[T]) Count(ctx context.Context, opts ...QueryOption) (int64, error) {
+	var count int64
+	err := r.query(ctx, opts).Count(&count).Error
+	return count, err
+}
+
+func (r *GormRepository[T]) Exists(ctx context.Context, opts ...QueryOption) (bool, error) {
+	var exists bool
+	err := r.query(ctx, opts).Select("count(*) > 0").Find(&exists).Error
+	return exists, err
+}
+
+func (r *GormRepository[T]) Create(ctx context.Context, entity *T) error {
+	return r.conn(ctx).Create(entity).Error
+}
+
+// Update saves all fields of entity, including zero values.
+func (r *GormRepository[T]) Update(ctx context.Context, entity *T) error {
+	return r.conn(ctx).Save(entity).Error
+}
+
+// SoftDelete sets deleted_at and deleted_by in a single statement.
+func (r *GormRepository[T]) SoftDelete(ctx context.Context, id uint) error {
+	tx := r.conn(ctx)
+	return tx.Model(new(T)).Where("id = ?", id).UpdateColumns(models.DeletedColumns(tx)).Error
+}
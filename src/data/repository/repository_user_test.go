@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"base_structure/src/data/models"
+	"base_structure/src/pkg/actor"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"testing"
+)
+
+func newTestDb(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())
+	database, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, database.AutoMigrate(&models.User{}, &models.Role{}, &models.RoleUser{}))
+	t.Cleanup(func() {
+		sqlDb, _ := database.DB()
+		_ = sqlDb.Close()
+	})
+	return database
+}
+
+func seedUsers(t *testing.T, database *gorm.DB, n int) (UserRepository, *models.Role) {
+	t.Helper()
+	role := models.Role{Name: "default"}
+	require.NoError(t, database.Create(&role).Error)
+	ctx := context.Background()
+	users := NewUserRepository(database)
+	for i := 1; i <= n; i++ {
+		u := models.User{Username: fmt.Sprintf("user_%02d", i), Email: fmt.Sprintf("u%d@example.com", i), Password: "x"}
+		require.NoError(t, users.CreateWithRole(ctx, &u, role.ID))
+	}
+	return users, &role
+}
+
+func TestUserRepositoryLookups(t *testing.T) {
+	ctx := context.Background()
+	database := newTestDb(t)
+	users, role := seedUsers(t, database, 3)
+
+	u, err := users.FindByUsername(ctx, "user_02")
+	require.NoError(t, err)
+	require.NotNil(t, u.RoleUsers)
+	require.Len(t, *u.RoleUsers, 1)
+	assert.Equal(t, role.Name, (*u.RoleUsers)[0].Role.Name)
+
+	exists, err := users.ExistsByEmail(ctx, "u3@example.com")
+	require.NoError(t, err)
+	assert.True(t, exists)
+	exists, err = users.ExistsByUsername(ctx, "nobody")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	_, err = users.FindByUsername(ctx, "nobody")
+	assert.True(t, errors.Is(err, gorm.ErrRecordNotFound))
+}
+
+func TestRepositoryListOptions(t *testing.T) {
+	ctx := context.Background()
+	database := newTestDb(t)
+	users, _ := seedUsers(t, database, 5)
+
+	page, err := users.List(ctx, Sort("username", true), Paginate(2, 2))
+	require.NoError(t, err)
+	require.Len(t, page, 2)
+	assert.Equal(t, "user_03", page[0].Username)
+	assert.Equal(t, "user_02", page[1].Username)
+
+	count, err := users.Count(ctx, Where("username > ?", "user_03"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+}
+
+func TestRepositoryUpdateAndSoftDelete(t *testing.T) {
+	ctx := context.Background()
+	database := newTestDb(t)
+	users, _ := seedUsers(t, database, 1)
+
+	u, err := users.FindByUsername(ctx, "user_01")
+	require.NoError(t, err)
+	u.FirstName = "Ali"
+	require.NoError(t, users.Update(ctx, u))
+	u, err = users.FindByID(ctx, u.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Ali", u.FirstName)
+
+	require.NoError(t, users.SoftDelete(ctx, u.ID))
+	_, err = users.FindByID(ctx, u.ID)
+	assert.True(t, errors.Is(err, gorm.ErrRecordNotFound))
+	var deleted models.User
+	require.NoError(t, database.Unscoped().First(&deleted, u.ID).Error)
+	assert.True(t, deleted.DeletedAt.Valid)
+}
+
+func TestRepositoryFillsAuditColumnsFromContext(t *testing.T) {
+	database := newTestDb(t)
+	users := NewUserRepository(database)
+	ctx := actor.WithUser(context.Background(), 42)
+
+	u := models.User{Username: "audited", Password: "x"}
+	require.NoError(t, users.Create(ctx, &u))
+	u.FirstName = "Ali"
+	require.NoError(t, users.Update(ctx, &u))
+
+	stored, err := users.FindByID(context.Background(), u.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 42, stored.CreatedBy)
+	require.NotNil(t, stored.UpdatedBy)
+	assert.Equal(t, int64(42), stored.UpdatedBy.Int64)
+
+	other := actor.WithUser(context.Background(), 7)
+	require.NoError(t, database.WithContext(other).Model(&models.User{}).Where("id = ?", u.ID).
+		Updates(map[string]interface{}{"first_name": "Reza"}).Error)
+	require.NoError(t, users.SoftDelete(ctx, u.ID))
+	var deleted models.User
+	require.NoError(t, database.Unscoped().First(&deleted, u.ID).Error)
+	assert.Equal(t, "Reza", deleted.FirstName)
+	assert.Equal(t, int64(7), deleted.UpdatedBy.Int64, "updates given a map of columns are attributed too")
+	assert.True(t, deleted.DeletedAt.Valid)
+	require.NotNil(t, deleted.DeletedBy)
+	assert.True(t, deleted.DeletedBy.Valid)
+	assert.Equal(t, int64(42), deleted.DeletedBy.Int64)
+
+	anonymous := models.User{Username: "anonymous", Password: "x"}
+	require.NoError(t, users.Create(context.Background(), &anonymous))
+	assert.Equal(t, actor.Unknown, anonymous.CreatedBy)
+}
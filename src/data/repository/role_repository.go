@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"base_structure/src/data/models"
+	"context"
+	"gorm.io/gorm"
+)
+
+type RoleRepository interface {
+	Repository[models.Role]
+	FindByName(ctx context.Context, name string) (*models.Role, error)
+}
+
+type roleRepository struct {
+	*GormRepository[models.Role]
+}
+
+func NewRoleRepository(database *gorm.DB) RoleRepository {
+	return &roleRepository{GormRepository: NewRepository[models.Role](database)}
+}
+
+func (r *roleRepository) FindByName(ctx context.Context, name string) (*models.Role, error) {
+	return r.FindOne(ctx, Filter("name", name))
+}
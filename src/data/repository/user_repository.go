@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"base_structure/src/data/db"
+	"base_structure/src/data/models"
+	"context"
+	"gorm.io/gorm"
+)
+
+type UserRepository interface {
+	Repository[models.User]
+	// FindByUsername and FindByMobileNumber load the user together with its roles.
+	FindByUsername(ctx context.Context, username string) (*models.User, error)
+	FindByMobileNumber(ctx context.Context, mobileNumber string) (*models.User, error)
+	ExistsByUsername(ctx context.Context, username string) (bool, error)
+	ExistsByEmail(ctx context.Context, email string) (bool, error)
+	ExistsByMobileNumber(ctx context.Context, mobileNumber string) (bool, error)
+	// CreateWithRole creates the user and assigns it roleId atomically, in a savepoint when ctx carries a transaction.
+	CreateWithRole(ctx context.Context, user *models.User, roleId uint) error
+}
+
+type userRepository struct {
+	*GormRepository[models.User]
+}
+
+func NewUserRepository(database *gorm.DB) UserRepository {
+	return &userRepository{GormRepository: NewRepository[models.User](database)}
+}
+
+// WithRoles preloads the user's role assignments and their roles.
+func WithRoles() QueryOption {
+	return Preload("RoleUsers.Role")
+}
+
+func (r *userRepository) FindByUsername(ctx context.Context, username string) (*models.User, error) {
+	return r.FindOne(ctx, Filter("username", username), WithRoles())
+}
+
+func (r *userRepository) FindByMobileNumber(ctx context.Context, mobileNumber string) (*models.User, error) {
+	return r.FindOne(ctx, Filter("mobile_number", mobileNumber), WithRoles())
+}
+
+func (r *userRepository) ExistsByUsername(ctx context.Context, username string) (bool, error) {
+	return r.Exists(ctx, Filter("username", username))
+}
+
+func (r *userRepository) ExistsByEmail(ctx context.Context, email string) (bool, error) {
+	return r.Exists(ctx, Filter("email", email))
+}
+
+func (r *userRepository) ExistsByMobileNumber(ctx context.Context, mobileNumber string) (bool, error) {
+	return r.Exists(ctx, Filter("mobile_number", mobileNumber))
+}
+
+func (r *userRepository) CreateWithRole(ctx context.Context, user *models.User, roleId uint) error {
+	return db.WithTransaction(ctx, r.database, func(ctx context.Context) error {
+		if err := r.Create(ctx, user); err != nil {
+			return err
+		}
+		return db.Conn(ctx, r.database).Create(&models.RoleUser{RoleId: roleId, UserId: user.ID}).Error
+	})
+}
@@ -0,0 +1,34 @@
+package actor
+
+import "context"
+
+const (
+	// Unknown is recorded when nobody is known to have made the change, e.g. a self-registration.
+	Unknown = -1
+	// System is recorded for changes made by migrations, seeders and background jobs. User ids start at 1.
+	System = 0
+)
+
+type key struct{}
+
+// WithUser returns a context that attributes database changes to userId.
+func WithUser(ctx context.Context, userId uint) context.Context {
+	return context.WithValue(ctx, key{}, int(userId))
+}
+
+// WithSystem returns a context that attributes database changes to the System actor.
+func WithSystem(ctx context.Context) context.Context {
+	return context.WithValue(ctx, key{}, System)
+}
+
+// FromContext returns the actor carried by ctx.
+func FromContext(ctx context.Context) (int, bool) {
+	if ctx == nil {
+		return Unknown, false
+	}
+	id, ok := ctx.Value(key{}).(int)
+	if !ok {
+		return Unknown, false
+	}
+	return id, true
+}
@@ -0,0 +1,43 @@
+package audit
+
+import "context"
+
+type Action string
+
+const (
+	Login            Action = "login"
+	LoginFailed      Action = "login_failed"
+	Register         Action = "register"
+	OtpSent          Action = "otp_sent"
+	OtpFailed        Action = "otp_failed"
+	Logout           Action = "logout"
+	AuthRejected     Action = "auth_rejected"
+	IdentityLinked   Action = "identity_linked"
+	IdentityUnlinked Action = "identity_unlinked"
+)
+
+const (
+	TargetUser             = "user"
+	TargetMobile           = "mobile"
+	TargetExternalIdentity = "external_identity"
+)
+
+// RequestInfo describes the HTTP request an event originates from.
+type RequestInfo struct {
+	Ip        string
+	UserAgent string
+	RequestId string
+}
+
+type requestInfoKey struct{}
+
+func WithRequestInfo(ctx context.Context, info RequestInfo) context.Context {
+	return context.WithValue(ctx, requestInfoKey{}, info)
+}
+
+// RequestInfoFromContext returns the request info stored by the RequestInfo middleware, or a zero value outside a
+// request.
+func RequestInfoFromContext(ctx context.Context) RequestInfo {
+	info, _ := ctx.Value(requestInfoKey{}).(RequestInfo)
+	return info
+}
@@ -0,0 +1,55 @@
+package audit
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+const redacted = "[REDACTED]"
+
+// Diff converts before and after to JSON objects and keeps only the fields that differ. Fields whose name contains
+// "password" are redacted. Either side may be nil, e.g. for a creation.
+func Diff(before, after interface{}) (map[string]interface{}, map[string]interface{}, error) {
+	b, err := toMap(before)
+	if err != nil {
+		return nil, nil, err
+	}
+	a, err := toMap(after)
+	if err != nil {
+		return nil, nil, err
+	}
+	if b != nil && a != nil {
+		for k, v := range b {
+			if reflect.DeepEqual(v, a[k]) {
+				delete(b, k)
+				delete(a, k)
+			}
+		}
+	}
+	return redact(b), redact(a), nil
+}
+
+func toMap(v interface{}) (map[string]interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]interface{}
+	if err = json.Unmarshal(raw, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func redact(m map[string]interface{}) map[string]interface{} {
+	for k := range m {
+		if strings.Contains(strings.ToLower(k), "password") {
+			m[k] = redacted
+		}
+	}
+	return m
+}
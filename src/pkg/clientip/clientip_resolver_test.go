@@ -0,0 +1,61 @@
+package clientip
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+func newRequest(remoteAddr string, hdr map[string]string) *http.Request {
+	req, _ := http.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = remoteAddr
+	for k, v := range hdr {
+		req.Header.Set(k, v)
+	}
+	return req
+}
+
+func TestResolverClientIP(t *testing.T) {
+	r, err := NewResolver([]string{"10.0.0.0/8", "192.0.2.10"}, []string{XForwardedFor, XRealIp, Forwarded})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		hdr        map[string]string
+		want       string
+	}{
+		{"direct client", "203.0.113.7:5000", nil, "203.0.113.7"},
+		{"untrusted peer can't spoof", "203.0.113.7:5000", map[string]string{XForwardedFor: "198.51.100.1"}, "203.0.113.7"},
+		{"trusted proxy", "10.0.0.1:5000", map[string]string{XForwardedFor: "198.51.100.1"}, "198.51.100.1"},
+		{"skips trusted hops", "10.0.0.1:5000", map[string]string{XForwardedFor: "198.51.100.1, 10.0.0.2, 192.0.2.10"}, "198.51.100.1"},
+		{"rightmost untrusted wins", "10.0.0.1:5000", map[string]string{XForwardedFor: "1.1.1.1, 198.51.100.1, 10.0.0.2"}, "198.51.100.1"},
+		{"garbage falls through", "10.0.0.1:5000", map[string]string{XForwardedFor: "nope", XRealIp: "198.51.100.2"}, "198.51.100.2"},
+		{"forwarded", "10.0.0.1:5000", map[string]string{Forwarded: `for=198.51.100.3;proto=https, for="10.0.0.5:80"`}, "198.51.100.3"},
+		{"forwarded ipv6", "10.0.0.1:5000", map[string]string{Forwarded: `for="[2001:db8::1]:4711"`}, "2001:db8::1"},
+		{"forwarded unknown stops", "10.0.0.1:5000", map[string]string{Forwarded: `for=198.51.100.3, for=unknown`}, "10.0.0.1"},
+		{"no header", "10.0.0.1:5000", nil, "10.0.0.1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, r.ClientIP(newRequest(tt.remoteAddr, tt.hdr)))
+		})
+	}
+}
+
+func TestResolverTrustsNoProxyByDefault(t *testing.T) {
+	r, err := NewResolver(nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultHeaders, r.Headers())
+	assert.Equal(t, "10.0.0.1", r.ClientIP(newRequest("10.0.0.1:5000", map[string]string{XForwardedFor: "198.51.100.1"})))
+}
+
+func TestNewResolverRejectsInvalidConfig(t *testing.T) {
+	_, err := NewResolver([]string{"10.0.0.0/33"}, nil)
+	assert.Error(t, err)
+	_, err = NewResolver([]string{"proxy"}, nil)
+	assert.Error(t, err)
+	_, err = NewResolver(nil, []string{"X-Client-Ip"})
+	assert.Error(t, err)
+}
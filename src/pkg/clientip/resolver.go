@@ -0,0 +1,147 @@
+package clientip
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+const (
+	XForwardedFor = "X-Forwarded-For"
+	XRealIp       = "X-Real-IP"
+	Forwarded     = "Forwarded"
+)
+
+// DefaultHeaders are consulted when no trusted headers are configured.
+var DefaultHeaders = []string{XForwardedFor, XRealIp}
+
+// Resolver finds the address of the client behind the trusted proxies. Headers are only believed when the request
+// arrives from a trusted proxy, and a forwarding chain is walked from the right, skipping trusted proxies, so a
+// client can't spoof its address by sending the header itself.
+type Resolver struct {
+	proxies []*net.IPNet
+	headers []string
+}
+
+// NewResolver accepts proxies as CIDRs or single addresses. Headers are tried in order; X-Forwarded-For, X-Real-IP and
+// Forwarded (RFC 7239) are understood.
+func NewResolver(proxies []string, headers []string) (*Resolver, error) {
+	r := &Resolver{headers: headers}
+	if len(r.headers) == 0 {
+		r.headers = DefaultHeaders
+	}
+	for _, h := range r.headers {
+		switch http.CanonicalHeaderKey(h) {
+		case http.CanonicalHeaderKey(XForwardedFor), http.CanonicalHeaderKey(XRealIp), Forwarded:
+		default:
+			return nil, fmt.Errorf("unsupported client ip header %q", h)
+		}
+	}
+	for _, p := range proxies {
+		if !strings.Contains(p, "/") {
+			ip := net.ParseIP(p)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q", p)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			r.proxies = append(r.proxies, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, cidr, err := net.ParseCIDR(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", p, err)
+		}
+		r.proxies = append(r.proxies, cidr)
+	}
+	return r, nil
+}
+
+// Headers returns the trusted headers in the order they are tried.
+func (r *Resolver) Headers() []string {
+	return r.headers
+}
+
+func (r *Resolver) ClientIP(req *http.Request) string {
+	remote, _, err := net.SplitHostPort(strings.TrimSpace(req.RemoteAddr))
+	if err != nil {
+		remote = strings.TrimSpace(req.RemoteAddr)
+	}
+	if !r.trusted(net.ParseIP(remote)) {
+		return remote
+	}
+	for _, h := range r.headers {
+		if ip, ok := r.fromChain(chain(h, req.Header)); ok {
+			return ip
+		}
+	}
+	return remote
+}
+
+// fromChain returns the rightmost address that isn't a trusted proxy.
+func (r *Resolver) fromChain(chain []string) (string, bool) {
+	for i := len(chain) - 1; i >= 0; i-- {
+		ip := net.ParseIP(chain[i])
+		if ip == nil {
+			return "", false
+		}
+		if i == 0 || !r.trusted(ip) {
+			return ip.String(), true
+		}
+	}
+	return "", false
+}
+
+func (r *Resolver) trusted(ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+	for _, p := range r.proxies {
+		if p.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// chain returns the addresses of a forwarding header, from the original client to the last proxy.
+func chain(header string, h http.Header) []string {
+	var addrs []string
+	for _, value := range h.Values(header) {
+		for _, part := range strings.Split(value, ",") {
+			part = strings.TrimSpace(part)
+			// An element without a usable for parameter stays in the chain, so the walk stops there instead of
+			// trusting whatever the client put in front of it.
+			if http.CanonicalHeaderKey(header) == Forwarded {
+				addrs = append(addrs, forwardedFor(part))
+			} else if part != "" {
+				addrs = append(addrs, part)
+			}
+		}
+	}
+	return addrs
+}
+
+// forwardedFor extracts the address of the for parameter of a Forwarded element, e.g. `for="[2001:db8::1]:4711"`.
+func forwardedFor(element string) string {
+	for _, pair := range strings.Split(element, ";") {
+		key, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || !strings.EqualFold(key, "for") {
+			continue
+		}
+		value = strings.Trim(value, `"`)
+		if strings.HasPrefix(value, "[") {
+			if end := strings.Index(value, "]"); end > 0 {
+				return value[1:end]
+			}
+		}
+		if host, _, err := net.SplitHostPort(value); err == nil {
+			return host
+		}
+		return value
+	}
+	return ""
+}
@@ -0,0 +1,111 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+
+	defaultTimeout = 2 * time.Second
+)
+
+// HealthChecker is a dependency the API needs to serve traffic. Check must return once ctx is done.
+type HealthChecker interface {
+	Name() string
+	Check(ctx context.Context) error
+}
+
+type checkerFunc struct {
+	name  string
+	check func(ctx context.Context) error
+}
+
+// NewChecker adapts a function to HealthChecker.
+func NewChecker(name string, check func(ctx context.Context) error) HealthChecker {
+	return &checkerFunc{name: name, check: check}
+}
+
+func (c *checkerFunc) Name() string {
+	return c.name
+}
+
+func (c *checkerFunc) Check(ctx context.Context) error {
+	return c.check(ctx)
+}
+
+type CheckResult struct {
+	Status     string `json:"status"`
+	DurationMs int64  `json:"durationMs"`
+	Error      string `json:"error,omitempty"`
+}
+
+type Report struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks"`
+}
+
+func (r *Report) Up() bool {
+	return r.Status == StatusUp
+}
+
+// Registry runs the registered checkers concurrently, each with its own timeout.
+type Registry struct {
+	mu       sync.RWMutex
+	checkers []HealthChecker
+	timeout  time.Duration
+}
+
+func NewRegistry(timeout time.Duration) *Registry {
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	return &Registry{timeout: timeout}
+}
+
+func (r *Registry) Register(checkers ...HealthChecker) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checkers = append(r.checkers, checkers...)
+}
+
+// Run checks every dependency. The report is up only if all of them are.
+func (r *Registry) Run(ctx context.Context) *Report {
+	r.mu.RLock()
+	checkers := r.checkers
+	r.mu.RUnlock()
+	results := make([]CheckResult, len(checkers))
+	var wg sync.WaitGroup
+	for i, c := range checkers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = r.check(ctx, c)
+		}()
+	}
+	wg.Wait()
+	report := &Report{Status: StatusUp, Checks: make(map[string]CheckResult, len(checkers))}
+	for i, c := range checkers {
+		report.Checks[c.Name()] = results[i]
+		if results[i].Status != StatusUp {
+			report.Status = StatusDown
+		}
+	}
+	return report
+}
+
+func (r *Registry) check(ctx context.Context, c HealthChecker) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	start := time.Now()
+	err := c.Check(ctx)
+	res := CheckResult{Status: StatusUp, DurationMs: time.Since(start).Milliseconds()}
+	if err != nil {
+		res.Status = StatusDown
+		res.Error = err.Error()
+	}
+	return res
+}
@@ -0,0 +1,39 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestRunReportsEveryCheck(t *testing.T) {
+	r := NewRegistry(time.Second)
+	r.Register(
+		NewChecker("postgres", func(context.Context) error { return nil }),
+		NewChecker("redis", func(context.Context) error { return errors.New("connection refused") }),
+	)
+
+	report := r.Run(context.Background())
+	assert.False(t, report.Up())
+	assert.Equal(t, StatusUp, report.Checks["postgres"].Status)
+	assert.Equal(t, StatusDown, report.Checks["redis"].Status)
+	assert.Equal(t, "connection refused", report.Checks["redis"].Error)
+}
+
+func TestRunAppliesTheTimeoutPerCheck(t *testing.T) {
+	r := NewRegistry(20 * time.Millisecond)
+	r.Register(NewChecker("slow", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}))
+
+	report := r.Run(context.Background())
+	assert.Equal(t, StatusDown, report.Checks["slow"].Status)
+	assert.Equal(t, context.DeadlineExceeded.Error(), report.Checks["slow"].Error)
+}
+
+func TestRunWithoutCheckersIsUp(t *testing.T) {
+	assert.True(t, NewRegistry(0).Run(context.Background()).Up())
+}
@@ -0,0 +1,93 @@
+package i18n
+
+import (
+	"context"
+	"golang.org/x/text/language"
+	"strings"
+)
+
+const (
+	Fa = "fa"
+	En = "en"
+
+	// Default is the language of the messages outside a request, and of keys missing from another catalog.
+	Default = En
+)
+
+var catalogs = map[string]map[string]string{
+	En: en,
+	Fa: fa,
+}
+
+// supported holds the languages of matcher, in the same order.
+var (
+	supported = []string{En, Fa}
+	matcher   = language.NewMatcher([]language.Tag{language.English, language.Persian})
+)
+
+type key struct{}
+
+// Supported reports whether lang has a catalog.
+func Supported(lang string) bool {
+	_, ok := catalogs[lang]
+	return ok
+}
+
+// Lookup returns the message of key in lang, falling back to the default catalog.
+func Lookup(lang string, key string) (string, bool) {
+	if msg, ok := catalogs[lang][key]; ok {
+		return msg, true
+	}
+	msg, ok := catalogs[Default][key]
+	return msg, ok
+}
+
+// T returns the message of key in lang with its {name} placeholders replaced by args. A key missing from every
+// catalog is returned as is.
+func T(lang string, key string, args map[string]string) string {
+	msg, ok := Lookup(lang, key)
+	if !ok {
+		return key
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	pairs := make([]string, 0, len(args)*2)
+	for name, value := range args {
+		pairs = append(pairs, "{"+name+"}", value)
+	}
+	return strings.NewReplacer(pairs...).Replace(msg)
+}
+
+// Negotiate returns the supported language preferred by an Accept-Language header, or fallback when the header names
+// none of them.
+func Negotiate(acceptLanguage string, fallback string) string {
+	if !Supported(fallback) {
+		fallback = Default
+	}
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(tags) == 0 {
+		return fallback
+	}
+	_, index, confidence := matcher.Match(tags...)
+	if confidence == language.No {
+		return fallback
+	}
+	return supported[index]
+}
+
+// WithLang returns a context carrying the language of the request.
+func WithLang(ctx context.Context, lang string) context.Context {
+	return context.WithValue(ctx, key{}, lang)
+}
+
+// FromContext returns the language carried by ctx, or Default outside a request.
+func FromContext(ctx context.Context) string {
+	if ctx == nil {
+		return Default
+	}
+	if lang, ok := ctx.Value(key{}).(string); ok {
+		return lang
+	}
+	return Default
+}
@@ -0,0 +1,55 @@
+package i18n
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestCatalogsHaveTheSameKeys(t *testing.T) {
+	for key := range en {
+		assert.Contains(t, fa, key)
+	}
+	for key := range fa {
+		assert.Contains(t, en, key)
+	}
+}
+
+func TestCatalogsKeepPlaceholders(t *testing.T) {
+	for key, msg := range en {
+		for _, placeholder := range []string{"{field}", "{param}"} {
+			assert.Equal(t, strings.Contains(msg, placeholder), strings.Contains(fa[key], placeholder), key)
+		}
+	}
+}
+
+func TestT(t *testing.T) {
+	args := map[string]string{"field": "mobileNumber", "param": "11"}
+
+	assert.Equal(t, "mobileNumber must be 11 characters in length", T(En, "validation.len", args))
+	assert.Equal(t, "طول mobileNumber باید 11 کاراکتر باشد", T(Fa, "validation.len", args))
+	assert.Equal(t, "otp used", T("de", "error.otp_used", nil))
+	assert.Equal(t, "missing.key", T(Fa, "missing.key", nil))
+}
+
+func TestNegotiate(t *testing.T) {
+	cases := map[string]string{
+		"":                          Fa,
+		"fa":                        Fa,
+		"fa-IR,fa;q=0.9":            Fa,
+		"en-US,en;q=0.9":            En,
+		"de-DE,en;q=0.5,fa;q=0.8":   Fa,
+		"de-DE":                     Fa,
+		"not a language header!!!!": Fa,
+	}
+	for header, want := range cases {
+		assert.Equal(t, want, Negotiate(header, Fa), header)
+	}
+	assert.Equal(t, En, Negotiate("", "de"))
+}
+
+func TestFromContext(t *testing.T) {
+	assert.Equal(t, Default, FromContext(context.Background()))
+	assert.Equal(t, Fa, FromContext(WithLang(context.Background(), Fa)))
+}
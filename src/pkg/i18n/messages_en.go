@@ -0,0 +1,117 @@
+package i18n
+
+// en is the English catalog. Its error messages are the EndUserMessage of the service errors.
+var en = map[string]string{
+	// Errors, keyed by service_errors.Kind.Key
+	"error.otp_exists":                   "otp exists",
+	"error.otp_used":                     "otp used",
+	"error.otp_not_valid":                "otp not valid",
+	"error.otp_expired":                  "otp expired",
+	"error.unexpected":                   "unexpected error",
+	"error.claims_not_found":             "claims not found",
+	"error.token_required":               "token required",
+	"error.token_expired":                "token expired",
+	"error.token_invalid":                "token invalid",
+	"error.email_exists":                 "email exists",
+	"error.username_exists":              "username exists",
+	"error.permission_denied":            "permission denied",
+	"error.invalid_credentials":          "invalid credentials",
+	"error.webauthn_challenge_not_found": "webauthn challenge not found",
+	"error.webauthn_verification_failed": "webauthn verification failed",
+	"error.webauthn_no_credentials":      "no webauthn credentials registered",
+	"error.oauth_provider_not_found":     "oauth provider not found",
+	"error.oauth_state_invalid":          "oauth state invalid",
+	"error.oauth_exchange_failed":        "oauth exchange failed",
+	"error.external_identity_linked":     "external identity already linked",
+	"error.external_identity_not_found":  "external identity not found",
+	"error.external_identity_last_login": "cannot unlink the last login method",
+	"error.too_many_requests":            "too many requests",
+	"error.otp_too_many_requests":        "not allowed",
+	"error.record_not_found":             "record not found",
+	"error.internal":                     "internal error",
+
+	// Validation messages, keyed by validator tag. {field} is the json name of the field and {param} the tag parameter.
+	"validation.required":                "{field} is required",
+	"validation.len":                     "{field} must be {param} characters in length",
+	"validation.min":                     "{field} must be at least {param}",
+	"validation.max":                     "{field} must be at most {param}",
+	"validation.eq":                      "{field} must be equal to {param}",
+	"validation.ne":                      "{field} must not be equal to {param}",
+	"validation.lt":                      "{field} must be less than {param}",
+	"validation.lte":                     "{field} must be less than or equal to {param}",
+	"validation.gt":                      "{field} must be greater than {param}",
+	"validation.gte":                     "{field} must be greater than or equal to {param}",
+	"validation.eqfield":                 "{field} must be equal to the value of {param}",
+	"validation.nefield":                 "{field} must not be equal to the value of {param}",
+	"validation.gtfield":                 "{field} must be greater than the value of {param}",
+	"validation.gtefield":                "{field} must be greater than or equal to the value of {param}",
+	"validation.ltfield":                 "{field} must be less than the value of {param}",
+	"validation.ltefield":                "{field} must be less than or equal to the value of {param}",
+	"validation.alpha":                   "{field} can only contain alphabetic characters",
+	"validation.alphanum":                "{field} can only contain alphanumeric characters",
+	"validation.numeric":                 "{field} must be a numeric value",
+	"validation.number":                  "{field} must be a valid number",
+	"validation.hexadecimal":             "{field} must be a valid hexadecimal",
+	"validation.hexcolor":                "{field} must be a valid HEX color code",
+	"validation.rgb":                     "{field} must be a valid RGB color code",
+	"validation.rgba":                    "{field} must be a valid RGBA color code",
+	"validation.hsl":                     "{field} must be a valid HSL color code",
+	"validation.hsla":                    "{field} must be a valid HSLA color code",
+	"validation.email":                   "{field} must be a valid email address",
+	"validation.url":                     "{field} must be a valid URL",
+	"validation.uri":                     "{field} must be a valid URI",
+	"validation.base64":                  "{field} must be a valid Base64 string",
+	"validation.contains":                "{field} must contain '{param}'",
+	"validation.containsany":             "{field} must contain at least one of the following characters: '{param}'",
+	"validation.containsrune":            "{field} must contain the rune '{param}'",
+	"validation.excludes":                "{field} must not contain '{param}'",
+	"validation.excludesall":             "{field} must not contain any of the following characters: '{param}'",
+	"validation.excludesrune":            "{field} must not contain the rune '{param}'",
+	"validation.isbn":                    "{field} must be a valid ISBN",
+	"validation.isbn10":                  "{field} must be a valid ISBN-10",
+	"validation.isbn13":                  "{field} must be a valid ISBN-13",
+	"validation.uuid":                    "{field} must be a valid UUID",
+	"validation.uuid3":                   "{field} must be a valid UUID v3",
+	"validation.uuid4":                   "{field} must be a valid UUID v4",
+	"validation.uuid5":                   "{field} must be a valid UUID v5",
+	"validation.ascii":                   "{field} must contain only ASCII characters",
+	"validation.printascii":              "{field} must contain only printable ASCII characters",
+	"validation.multibyte":               "{field} must contain multibyte characters",
+	"validation.datauri":                 "{field} must be a valid Data URI",
+	"validation.latitude":                "{field} must be a valid latitude coordinate",
+	"validation.longitude":               "{field} must be a valid longitude coordinate",
+	"validation.ssn":                     "{field} must be a valid SSN",
+	"validation.ip":                      "{field} must be a valid IP address",
+	"validation.ipv4":                    "{field} must be a valid IPv4 address",
+	"validation.ipv6":                    "{field} must be a valid IPv6 address",
+	"validation.cidr":                    "{field} must be a valid CIDR notation IP address",
+	"validation.cidrv4":                  "{field} must be a valid CIDR notation IPv4 address",
+	"validation.cidrv6":                  "{field} must be a valid CIDR notation IPv6 address",
+	"validation.tcp4_addr":               "{field} must be a valid TCPv4 address",
+	"validation.tcp6_addr":               "{field} must be a valid TCPv6 address",
+	"validation.tcp_addr":                "{field} must be a valid TCP address",
+	"validation.udp4_addr":               "{field} must be a valid UDPv4 address",
+	"validation.udp6_addr":               "{field} must be a valid UDPv6 address",
+	"validation.udp_addr":                "{field} must be a valid UDP address",
+	"validation.ip_addr":                 "{field} must be a resolvable IP address",
+	"validation.unix_addr":               "{field} must be a resolvable Unix address",
+	"validation.mac":                     "{field} must be a valid MAC address",
+	"validation.hostname":                "{field} must be a valid hostname",
+	"validation.fqdn":                    "{field} must be a valid fully qualified domain name",
+	"validation.unique":                  "{field} must contain unique values",
+	"validation.oneof":                   "{field} must be one of [{param}]",
+	"validation.datetime":                "{field} must be a valid datetime in the format '{param}'",
+	"validation.dir":                     "{field} must be a valid directory",
+	"validation.file":                    "{field} must be a valid file",
+	"validation.base64url":               "{field} must be a valid Base64 URL-encoded string",
+	"validation.btc_addr":                "{field} must be a valid Bitcoin address",
+	"validation.btc_addr_bech32":         "{field} must be a valid Bech32 Bitcoin address",
+	"validation.eth_addr":                "{field} must be a valid Ethereum address",
+	"validation.hostname_port":           "{field} must be a valid hostname with port",
+	"validation.hostname_rfc1123":        "{field} must be a valid hostname according to RFC 1123",
+	"validation.postcode_iso3166_alpha2": "{field} must be a valid postcode for ISO 3166-1 alpha-2 country code",
+	"validation.postcode_iso3166_alpha3": "{field} must be a valid postcode for ISO 3166-1 alpha-3 country code",
+	"validation.ir_mobile":               "{field} must be in IR mobile number format",
+	"validation.password":                "{field} is not safe enough",
+	"validation.default":                 "{field} is invalid",
+}
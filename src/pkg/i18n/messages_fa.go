@@ -0,0 +1,117 @@
+package i18n
+
+// fa is the Persian catalog.
+var fa = map[string]string{
+	// Errors, keyed by service_errors.Kind.Key
+	"error.otp_exists":                   "کد یکبار مصرف قبلاً ارسال شده است",
+	"error.otp_used":                     "کد یکبار مصرف قبلاً استفاده شده است",
+	"error.otp_not_valid":                "کد یکبار مصرف نامعتبر است",
+	"error.otp_expired":                  "کد یکبار مصرف منقضی شده است",
+	"error.unexpected":                   "خطای غیرمنتظره",
+	"error.claims_not_found":             "اطلاعات توکن یافت نشد",
+	"error.token_required":               "توکن الزامی است",
+	"error.token_expired":                "توکن منقضی شده است",
+	"error.token_invalid":                "توکن نامعتبر است",
+	"error.email_exists":                 "این ایمیل قبلاً ثبت شده است",
+	"error.username_exists":              "این نام کاربری قبلاً ثبت شده است",
+	"error.permission_denied":            "دسترسی مجاز نیست",
+	"error.invalid_credentials":          "اطلاعات ورود نادرست است",
+	"error.webauthn_challenge_not_found": "چالش WebAuthn یافت نشد یا منقضی شده است",
+	"error.webauthn_verification_failed": "تأیید WebAuthn ناموفق بود",
+	"error.webauthn_no_credentials":      "هیچ کلید WebAuthn ثبت نشده است",
+	"error.oauth_provider_not_found":     "ارائه‌دهنده OAuth یافت نشد",
+	"error.oauth_state_invalid":          "state درخواست OAuth نامعتبر است",
+	"error.oauth_exchange_failed":        "دریافت توکن از ارائه‌دهنده OAuth ناموفق بود",
+	"error.external_identity_linked":     "این حساب خارجی قبلاً متصل شده است",
+	"error.external_identity_not_found":  "حساب خارجی یافت نشد",
+	"error.external_identity_last_login": "آخرین روش ورود را نمی‌توان جدا کرد",
+	"error.too_many_requests":            "تعداد درخواست‌ها بیش از حد مجاز است",
+	"error.otp_too_many_requests":        "مجاز نیست",
+	"error.record_not_found":             "رکورد یافت نشد",
+	"error.internal":                     "خطای داخلی",
+
+	// Validation messages, keyed by validator tag. {field} is the json name of the field and {param} the tag parameter.
+	"validation.required":                "{field} الزامی است",
+	"validation.len":                     "طول {field} باید {param} کاراکتر باشد",
+	"validation.min":                     "{field} باید حداقل {param} باشد",
+	"validation.max":                     "{field} باید حداکثر {param} باشد",
+	"validation.eq":                      "{field} باید برابر با {param} باشد",
+	"validation.ne":                      "{field} نباید برابر با {param} باشد",
+	"validation.lt":                      "{field} باید کمتر از {param} باشد",
+	"validation.lte":                     "{field} باید کمتر یا مساوی {param} باشد",
+	"validation.gt":                      "{field} باید بیشتر از {param} باشد",
+	"validation.gte":                     "{field} باید بیشتر یا مساوی {param} باشد",
+	"validation.eqfield":                 "{field} باید با مقدار {param} برابر باشد",
+	"validation.nefield":                 "{field} نباید با مقدار {param} برابر باشد",
+	"validation.gtfield":                 "{field} باید بیشتر از مقدار {param} باشد",
+	"validation.gtefield":                "{field} باید بیشتر یا مساوی مقدار {param} باشد",
+	"validation.ltfield":                 "{field} باید کمتر از مقدار {param} باشد",
+	"validation.ltefield":                "{field} باید کمتر یا مساوی مقدار {param} باشد",
+	"validation.alpha":                   "{field} فقط می‌تواند شامل حروف الفبا باشد",
+	"validation.alphanum":                "{field} فقط می‌تواند شامل حروف و اعداد باشد",
+	"validation.numeric":                 "{field} باید یک مقدار عددی باشد",
+	"validation.number":                  "{field} باید یک عدد معتبر باشد",
+	"validation.hexadecimal":             "{field} باید یک مقدار هگزادسیمال معتبر باشد",
+	"validation.hexcolor":                "{field} باید یک کد رنگ HEX معتبر باشد",
+	"validation.rgb":                     "{field} باید یک کد رنگ RGB معتبر باشد",
+	"validation.rgba":                    "{field} باید یک کد رنگ RGBA معتبر باشد",
+	"validation.hsl":                     "{field} باید یک کد رنگ HSL معتبر باشد",
+	"validation.hsla":                    "{field} باید یک کد رنگ HSLA معتبر باشد",
+	"validation.email":                   "{field} باید یک آدرس ایمیل معتبر باشد",
+	"validation.url":                     "{field} باید یک URL معتبر باشد",
+	"validation.uri":                     "{field} باید یک URI معتبر باشد",
+	"validation.base64":                  "{field} باید یک رشته Base64 معتبر باشد",
+	"validation.contains":                "{field} باید شامل '{param}' باشد",
+	"validation.containsany":             "{field} باید حداقل یکی از این کاراکترها را داشته باشد: '{param}'",
+	"validation.containsrune":            "{field} باید شامل کاراکتر '{param}' باشد",
+	"validation.excludes":                "{field} نباید شامل '{param}' باشد",
+	"validation.excludesall":             "{field} نباید هیچ‌یک از این کاراکترها را داشته باشد: '{param}'",
+	"validation.excludesrune":            "{field} نباید شامل کاراکتر '{param}' باشد",
+	"validation.isbn":                    "{field} باید یک ISBN معتبر باشد",
+	"validation.isbn10":                  "{field} باید یک ISBN-10 معتبر باشد",
+	"validation.isbn13":                  "{field} باید یک ISBN-13 معتبر باشد",
+	"validation.uuid":                    "{field} باید یک UUID معتبر باشد",
+	"validation.uuid3":                   "{field} باید یک UUID نسخه ۳ معتبر باشد",
+	"validation.uuid4":                   "{field} باید یک UUID نسخه ۴ معتبر باشد",
+	"validation.uuid5":                   "{field} باید یک UUID نسخه ۵ معتبر باشد",
+	"validation.ascii":                   "{field} فقط می‌تواند شامل کاراکترهای ASCII باشد",
+	"validation.printascii":              "{field} فقط می‌تواند شامل کاراکترهای قابل چاپ ASCII باشد",
+	"validation.multibyte":               "{field} باید شامل کاراکترهای چندبایتی باشد",
+	"validation.datauri":                 "{field} باید یک Data URI معتبر باشد",
+	"validation.latitude":                "{field} باید یک عرض جغرافیایی معتبر باشد",
+	"validation.longitude":               "{field} باید یک طول جغرافیایی معتبر باشد",
+	"validation.ssn":                     "{field} باید یک SSN معتبر باشد",
+	"validation.ip":                      "{field} باید یک آدرس IP معتبر باشد",
+	"validation.ipv4":                    "{field} باید یک آدرس IPv4 معتبر باشد",
+	"validation.ipv6":                    "{field} باید یک آدرس IPv6 معتبر باشد",
+	"validation.cidr":                    "{field} باید یک آدرس IP معتبر با نماد CIDR باشد",
+	"validation.cidrv4":                  "{field} باید یک آدرس IPv4 معتبر با نماد CIDR باشد",
+	"validation.cidrv6":                  "{field} باید یک آدرس IPv6 معتبر با نماد CIDR باشد",
+	"validation.tcp4_addr":               "{field} باید یک آدرس TCPv4 معتبر باشد",
+	"validation.tcp6_addr":               "{field} باید یک آدرس TCPv6 معتبر باشد",
+	"validation.tcp_addr":                "{field} باید یک آدرس TCP معتبر باشد",
+	"validation.udp4_addr":               "{field} باید یک آدرس UDPv4 معتبر باشد",
+	"validation.udp6_addr":               "{field} باید یک آدرس UDPv6 معتبر باشد",
+	"validation.udp_addr":                "{field} باید یک آدرس UDP معتبر باشد",
+	"validation.ip_addr":                 "{field} باید یک آدرس IP قابل resolve باشد",
+	"validation.unix_addr":               "{field} باید یک آدرس Unix قابل resolve باشد",
+	"validation.mac":                     "{field} باید یک آدرس MAC معتبر باشد",
+	"validation.hostname":                "{field} باید یک نام میزبان معتبر باشد",
+	"validation.fqdn":                    "{field} باید یک نام دامنه کامل معتبر باشد",
+	"validation.unique":                  "مقادیر {field} باید یکتا باشند",
+	"validation.oneof":                   "{field} باید یکی از [{param}] باشد",
+	"validation.datetime":                "{field} باید تاریخ و زمانی معتبر با قالب '{param}' باشد",
+	"validation.dir":                     "{field} باید یک پوشه معتبر باشد",
+	"validation.file":                    "{field} باید یک فایل معتبر باشد",
+	"validation.base64url":               "{field} باید یک رشته Base64 URL معتبر باشد",
+	"validation.btc_addr":                "{field} باید یک آدرس بیت‌کوین معتبر باشد",
+	"validation.btc_addr_bech32":         "{field} باید یک آدرس بیت‌کوین Bech32 معتبر باشد",
+	"validation.eth_addr":                "{field} باید یک آدرس اتریوم معتبر باشد",
+	"validation.hostname_port":           "{field} باید یک نام میزبان معتبر همراه با پورت باشد",
+	"validation.hostname_rfc1123":        "{field} باید یک نام میزبان معتبر مطابق RFC 1123 باشد",
+	"validation.postcode_iso3166_alpha2": "{field} باید یک کد پستی معتبر برای کد کشور ISO 3166-1 alpha-2 باشد",
+	"validation.postcode_iso3166_alpha3": "{field} باید یک کد پستی معتبر برای کد کشور ISO 3166-1 alpha-3 باشد",
+	"validation.ir_mobile":               "{field} باید یک شماره موبایل معتبر ایران باشد",
+	"validation.password":                "{field} به اندازه کافی امن نیست",
+	"validation.default":                 "{field} نامعتبر است",
+}
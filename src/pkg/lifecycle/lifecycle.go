@@ -0,0 +1,79 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+type StopFunc func(ctx context.Context) error
+
+type hook struct {
+	name string
+	stop StopFunc
+}
+
+// Registry collects what has to be released on shutdown and tracks whether the process is ready for traffic.
+// Components register a hook right after they start, so stopping them in reverse order closes every component
+// before the ones it depends on.
+type Registry struct {
+	mu    sync.Mutex
+	hooks []hook
+	ready atomic.Bool
+}
+
+func New() *Registry {
+	return &Registry{}
+}
+
+// OnStop registers a hook to run on Stop.
+func (r *Registry) OnStop(name string, stop StopFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.hooks = append(r.hooks, hook{name: name, stop: stop})
+}
+
+// Stop runs the hooks last registered first. A hook still running when ctx is done is abandoned, so one stuck
+// component doesn't keep the others from closing. Stop marks the process not ready and returns every hook error.
+func (r *Registry) Stop(ctx context.Context) error {
+	r.SetReady(false)
+	r.mu.Lock()
+	hooks := r.hooks
+	r.hooks = nil
+	r.mu.Unlock()
+	var errs []error
+	for i := len(hooks) - 1; i >= 0; i-- {
+		if err := run(ctx, hooks[i]); err != nil {
+			errs = append(errs, fmt.Errorf("stopping %s: %w", hooks[i].name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func run(ctx context.Context, h hook) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- h.stop(ctx)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (r *Registry) SetReady(ready bool) {
+	r.ready.Store(ready)
+}
+
+// Ready reports whether the process accepts traffic. It turns false as soon as shutdown begins, before connections
+// are drained.
+func (r *Registry) Ready() bool {
+	return r.ready.Load()
+}
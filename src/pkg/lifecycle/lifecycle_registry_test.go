@@ -0,0 +1,58 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestStopRunsHooksInReverseOrder(t *testing.T) {
+	r := New()
+	var order []string
+	for _, name := range []string{"logger", "postgres", "audit"} {
+		r.OnStop(name, func(context.Context) error {
+			order = append(order, name)
+			return nil
+		})
+	}
+	r.SetReady(true)
+
+	assert.NoError(t, r.Stop(context.Background()))
+	assert.Equal(t, []string{"audit", "postgres", "logger"}, order)
+	assert.False(t, r.Ready())
+}
+
+func TestStopCollectsErrorsAndContinues(t *testing.T) {
+	r := New()
+	boom := errors.New("boom")
+	closed := false
+	r.OnStop("postgres", func(context.Context) error {
+		closed = true
+		return nil
+	})
+	r.OnStop("redis", func(context.Context) error {
+		return boom
+	})
+
+	err := r.Stop(context.Background())
+	assert.ErrorIs(t, err, boom)
+	assert.ErrorContains(t, err, "stopping redis")
+	assert.True(t, closed)
+}
+
+func TestStopAbandonsHooksPastTheDeadline(t *testing.T) {
+	r := New()
+	release := make(chan struct{})
+	defer close(release)
+	r.OnStop("stuck", func(context.Context) error {
+		<-release
+		return nil
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err := r.Stop(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
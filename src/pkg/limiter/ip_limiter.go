@@ -1,41 +1,106 @@
 package limiter
 
 import (
+	"container/list"
+	"context"
+	"expvar"
 	"golang.org/x/time/rate"
 	"sync"
+	"time"
 )
 
+// entries publishes the number of tracked IPs of every named limiter under /debug/vars.
+var entries = expvar.NewMap("ip_rate_limiter_entries")
+
+// IpRateLimiter keeps a token bucket per client IP in process memory. It holds at most maxEntries buckets, evicting
+// the least recently used one, and a janitor drops buckets idle for longer than idleTimeout. An idle timeout of at
+// least b/r loses nothing, since such a bucket is full again anyway.
 type IpRateLimiter struct {
-	ips map[string]*rate.Limiter
-	mu  *sync.RWMutex
-	r   rate.Limit
-	b   int
-}
-
-func NewIpRateLimiter(r rate.Limit, b int) *IpRateLimiter {
-	return &IpRateLimiter{
-		ips: make(map[string]*rate.Limiter),
-		mu:  &sync.RWMutex{},
-		r:   r,
-		b:   b,
+	mu          sync.Mutex
+	ips         map[string]*list.Element
+	lru         *list.List
+	r           rate.Limit
+	b           int
+	maxEntries  int
+	idleTimeout time.Duration
+	now         func() time.Time
+}
+
+type ipEntry struct {
+	ip       string
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// NewIpRateLimiter creates a limiter published as name. Its janitor runs until ctx is done. A maxEntries or
+// idleTimeout of zero disables the cap or the janitor.
+func NewIpRateLimiter(ctx context.Context, name string, r rate.Limit, b int, maxEntries int, idleTimeout time.Duration) *IpRateLimiter {
+	l := &IpRateLimiter{
+		ips:         make(map[string]*list.Element),
+		lru:         list.New(),
+		r:           r,
+		b:           b,
+		maxEntries:  maxEntries,
+		idleTimeout: idleTimeout,
+		now:         time.Now,
 	}
+	entries.Set(name, expvar.Func(func() any { return l.Len() }))
+	go l.janitor(ctx, name)
+	return l
 }
 
-func (l *IpRateLimiter) AddIp(ip string) *rate.Limiter {
+func (l *IpRateLimiter) GetLimiter(ip string) *rate.Limiter {
 	l.mu.Lock()
 	defer l.mu.Unlock()
-	limiter := rate.NewLimiter(l.r, l.b)
-	l.ips[ip] = limiter
-	return limiter
+	if el, ok := l.ips[ip]; ok {
+		e := el.Value.(*ipEntry)
+		e.lastSeen = l.now()
+		l.lru.MoveToFront(el)
+		return e.limiter
+	}
+	e := &ipEntry{ip: ip, limiter: rate.NewLimiter(l.r, l.b), lastSeen: l.now()}
+	l.ips[ip] = l.lru.PushFront(e)
+	if l.maxEntries > 0 && l.lru.Len() > l.maxEntries {
+		l.remove(l.lru.Back())
+	}
+	return e.limiter
 }
 
-func (l *IpRateLimiter) GetLimiter(ip string) *rate.Limiter {
+func (l *IpRateLimiter) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.lru.Len()
+}
+
+func (l *IpRateLimiter) janitor(ctx context.Context, name string) {
+	defer entries.Delete(name)
+	if l.idleTimeout <= 0 {
+		<-ctx.Done()
+		return
+	}
+	ticker := time.NewTicker(l.idleTimeout / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			l.evictIdle()
+		}
+	}
+}
+
+// evictIdle walks the list from the least recently used end and stops at the first bucket still in use.
+func (l *IpRateLimiter) evictIdle() {
 	l.mu.Lock()
-	limiter, exists := l.ips[ip]
-	if !exists {
-		l.mu.Unlock()
-		return l.AddIp(ip)
+	defer l.mu.Unlock()
+	deadline := l.now().Add(-l.idleTimeout)
+	for el := l.lru.Back(); el != nil && el.Value.(*ipEntry).lastSeen.Before(deadline); el = l.lru.Back() {
+		l.remove(el)
 	}
-	l.mu.Unlock()
-	return limiter
+}
+
+func (l *IpRateLimiter) remove(el *list.Element) {
+	l.lru.Remove(el)
+	delete(l.ips, el.Value.(*ipEntry).ip)
 }
@@ -0,0 +1,60 @@
+package limiter
+
+import (
+	"context"
+	"expvar"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+	"testing"
+	"time"
+)
+
+func TestIpRateLimiterEvictsLeastRecentlyUsed(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	l := NewIpRateLimiter(ctx, t.Name(), rate.Every(time.Second), 1, 2, 0)
+
+	a := l.GetLimiter("192.0.2.1")
+	l.GetLimiter("192.0.2.2")
+	assert.Same(t, a, l.GetLimiter("192.0.2.1"))
+	l.GetLimiter("192.0.2.3")
+
+	assert.Equal(t, 2, l.Len())
+	assert.Same(t, a, l.GetLimiter("192.0.2.1"))
+	assert.Equal(t, 2, l.Len())
+	_, ok := l.ips["192.0.2.2"]
+	assert.False(t, ok)
+}
+
+func TestIpRateLimiterEvictsIdleEntries(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	l := NewIpRateLimiter(ctx, t.Name(), rate.Every(time.Second), 1, 0, time.Minute)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	l.now = func() time.Time { return now }
+
+	l.GetLimiter("192.0.2.1")
+	now = now.Add(30 * time.Second)
+	l.GetLimiter("192.0.2.2")
+	now = now.Add(45 * time.Second)
+	l.evictIdle()
+
+	assert.Equal(t, 1, l.Len())
+	_, ok := l.ips["192.0.2.2"]
+	assert.True(t, ok)
+}
+
+func TestIpRateLimiterPublishesSize(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	l := NewIpRateLimiter(ctx, t.Name(), rate.Every(time.Second), 1, 0, 0)
+	l.GetLimiter("192.0.2.1")
+
+	v := entries.Get(t.Name())
+	require.NotNil(t, v)
+	assert.Equal(t, "1", v.String())
+
+	cancel()
+	assert.Eventually(t, func() bool { return entries.Get(t.Name()) == nil }, time.Second, 10*time.Millisecond)
+	assert.NotNil(t, expvar.Get("ip_rate_limiter_entries"))
+}
@@ -0,0 +1,101 @@
+package limiter
+
+import (
+	"context"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v7"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+// advanceFunc moves the clock the store under test reads forward.
+type advanceFunc func(d time.Duration)
+
+func newMemoryStore(_ *testing.T) (Store, advanceFunc) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	s := NewMemoryStore()
+	s.now = func() time.Time { return now }
+	return s, func(d time.Duration) { now = now.Add(d) }
+}
+
+func newRedisStore(t *testing.T) (Store, advanceFunc) {
+	mr := miniredis.RunT(t)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	mr.SetTime(now)
+	return NewRedisStore(redis.NewClient(&redis.Options{Addr: mr.Addr()})), func(d time.Duration) {
+		now = now.Add(d)
+		mr.SetTime(now)
+		mr.FastForward(d)
+	}
+}
+
+var stores = map[string]func(t *testing.T) (Store, advanceFunc){
+	"memory": newMemoryStore,
+	"redis":  newRedisStore,
+}
+
+func TestStoreAllowsBurstThenRejects(t *testing.T) {
+	limit := Limit{Rate: 2, Period: time.Second, Burst: 3}
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			store, _ := newStore(t)
+			for i := 2; i >= 0; i-- {
+				res, err := store.Allow(ctx, "k", limit)
+				require.NoError(t, err)
+				assert.True(t, res.Allowed)
+				assert.Equal(t, 3, res.Limit)
+				assert.Equal(t, i, res.Remaining)
+			}
+			res, err := store.Allow(ctx, "k", limit)
+			require.NoError(t, err)
+			assert.False(t, res.Allowed)
+			assert.Equal(t, 0, res.Remaining)
+			assert.Equal(t, 500*time.Millisecond, res.RetryAfter)
+			assert.Equal(t, 1500*time.Millisecond, res.ResetAfter)
+
+			other, err := store.Allow(ctx, "other", limit)
+			require.NoError(t, err)
+			assert.True(t, other.Allowed)
+		})
+	}
+}
+
+func TestStoreRefillsOverTime(t *testing.T) {
+	limit := Limit{Rate: 1, Period: time.Second, Burst: 1}
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			store, advance := newStore(t)
+			res, err := store.Allow(ctx, "k", limit)
+			require.NoError(t, err)
+			require.True(t, res.Allowed)
+
+			advance(400 * time.Millisecond)
+			res, err = store.Allow(ctx, "k", limit)
+			require.NoError(t, err)
+			assert.False(t, res.Allowed)
+			assert.Equal(t, 600*time.Millisecond, res.RetryAfter)
+
+			advance(600 * time.Millisecond)
+			res, err = store.Allow(ctx, "k", limit)
+			require.NoError(t, err)
+			assert.True(t, res.Allowed)
+		})
+	}
+}
+
+func TestStoreBurstDefaultsToRate(t *testing.T) {
+	limit := Limit{Rate: 5, Period: time.Minute}
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			store, _ := newStore(t)
+			res, err := store.Allow(context.Background(), "k", limit)
+			require.NoError(t, err)
+			assert.Equal(t, 5, res.Limit)
+			assert.Equal(t, 4, res.Remaining)
+		})
+	}
+}
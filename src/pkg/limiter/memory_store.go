@@ -0,0 +1,46 @@
+package limiter
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const memorySweepEvery = 1024
+
+// MemoryStore keeps the counters in process memory. Every replica counts on its own, so it is meant for tests and
+// single-instance development setups.
+type MemoryStore struct {
+	mu    sync.Mutex
+	tats  map[string]time.Time
+	calls int
+	now   func() time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{tats: make(map[string]time.Time), now: time.Now}
+}
+
+func (s *MemoryStore) Allow(_ context.Context, key string, limit Limit) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	s.calls++
+	if s.calls%memorySweepEvery == 0 {
+		s.sweep(now)
+	}
+	tat, res := gcra(now, s.tats[key], limit)
+	if res.Allowed {
+		s.tats[key] = tat
+	}
+	return res, nil
+}
+
+// sweep drops the keys that are idle again, which is what an expired key is in the Redis store.
+func (s *MemoryStore) sweep(now time.Time) {
+	for key, tat := range s.tats {
+		if !tat.After(now) {
+			delete(s.tats, key)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package limiter
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-redis/redis/v7"
+	"time"
+)
+
+// gcraScript is the Lua twin of gcra. It reads the clock of the Redis server, so replicas with skewed clocks still
+// share one limit, and it stores the tat in microseconds with an expiry at the moment the key becomes idle.
+var gcraScript = redis.NewScript(`
+redis.replicate_commands()
+local burst = tonumber(ARGV[1])
+local interval = tonumber(ARGV[2])
+local time = redis.call("TIME")
+local now = tonumber(time[1]) * 1000000 + tonumber(time[2])
+local tat = tonumber(redis.call("GET", KEYS[1]))
+if not tat or tat < now then
+	tat = now
+end
+local new_tat = tat + interval
+local diff = now - (new_tat - burst * interval)
+if diff < 0 then
+	return {0, 0, tat - now, -diff}
+end
+local reset_after = new_tat - now
+redis.call("SET", KEYS[1], string.format("%.0f", new_tat), "PX", math.ceil(reset_after / 1000))
+return {1, math.floor(diff / interval), reset_after, 0}
+`)
+
+// RedisStore shares the counters between every replica of the API.
+type RedisStore struct {
+	client *redis.Client
+}
+
+func NewRedisStore(client *redis.Client) *RedisStore {
+	return &RedisStore{client: client}
+}
+
+func (s *RedisStore) Allow(ctx context.Context, key string, limit Limit) (Result, error) {
+	burst := limit.burst()
+	interval := limit.interval().Microseconds()
+	reply, err := gcraScript.Run(s.client.WithContext(ctx), []string{key}, burst, interval).Result()
+	if err != nil {
+		return Result{}, err
+	}
+	values, ok := reply.([]interface{})
+	if !ok || len(values) != 4 {
+		return Result{}, fmt.Errorf("unexpected rate limit script reply %v", reply)
+	}
+	fields := make([]int64, len(values))
+	for i, v := range values {
+		if fields[i], ok = v.(int64); !ok {
+			return Result{}, fmt.Errorf("unexpected rate limit script reply %v", reply)
+		}
+	}
+	return Result{
+		Allowed:    fields[0] == 1,
+		Limit:      burst,
+		Remaining:  int(fields[1]),
+		ResetAfter: time.Duration(fields[2]) * time.Microsecond,
+		RetryAfter: time.Duration(fields[3]) * time.Microsecond,
+	}, nil
+}
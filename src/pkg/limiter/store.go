@@ -0,0 +1,64 @@
+package limiter
+
+import (
+	"context"
+	"time"
+)
+
+// Limit allows Rate requests per Period on average, with bursts of up to Burst requests.
+type Limit struct {
+	Rate   int
+	Period time.Duration
+	Burst  int
+}
+
+// interval is the time one request costs, i.e. how long it takes for one slot of the burst to refill.
+func (l Limit) interval() time.Duration {
+	return l.Period / time.Duration(l.Rate)
+}
+
+func (l Limit) burst() int {
+	if l.Burst <= 0 {
+		return l.Rate
+	}
+	return l.Burst
+}
+
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// ResetAfter is the time until the whole burst is available again.
+	ResetAfter time.Duration
+	// RetryAfter is the time until the next request is allowed. It is zero for allowed requests.
+	RetryAfter time.Duration
+}
+
+// Store counts requests per key with the generic cell rate algorithm (GCRA). For every key it keeps only the
+// theoretical arrival time (tat) of the next request, the moment at which the key would be fully idle again.
+type Store interface {
+	Allow(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// gcra decides a single request arriving at now for a key whose stored tat is tat. It returns the tat to store when
+// the request is allowed.
+func gcra(now, tat time.Time, limit Limit) (time.Time, Result) {
+	interval := limit.interval()
+	burst := limit.burst()
+	if tat.Before(now) {
+		tat = now
+	}
+	newTat := tat.Add(interval)
+	allowAt := newTat.Add(-time.Duration(burst) * interval)
+	diff := now.Sub(allowAt)
+	res := Result{Limit: burst}
+	if diff < 0 {
+		res.ResetAfter = tat.Sub(now)
+		res.RetryAfter = -diff
+		return tat, res
+	}
+	res.Allowed = true
+	res.Remaining = int(diff / interval)
+	res.ResetAfter = newTat.Sub(now)
+	return newTat, res
+}
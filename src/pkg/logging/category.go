@@ -23,6 +23,8 @@ const (
 
 	// Migration => Postgres
 	Migration SubCategory = "Migration"
+	// Seed => Postgres
+	Seed SubCategory = "Seed"
 	// Select => Postgres
 	Select SubCategory = "Select"
 	// Rollback => Postgres
@@ -42,27 +44,45 @@ const (
 	HashPassword SubCategory = "HashPassword"
 	// DefaultRoleNotFound => Internal
 	DefaultRoleNotFound SubCategory = "DefaultRoleNotFound"
+	// WebAuthn => Internal
+	WebAuthn SubCategory = "WebAuthn"
+	// OAuth => General
+	OAuth SubCategory = "OAuth"
+	// Audit => Internal, Postgres
+	Audit SubCategory = "Audit"
+	// RateLimit => Internal, Redis
+	RateLimit SubCategory = "RateLimit"
+	// LogLevel => Internal
+	LogLevel SubCategory = "LogLevel"
 
 	// MobileValidation => Validation
 	MobileValidation SubCategory = "MobileValidation"
 	// PasswordValidation => Validation
 	PasswordValidation SubCategory = "PasswordValidation"
+	// NormalizeTags => Validation
+	NormalizeTags SubCategory = "NormalizeTags"
 
 	// RemoveFile => IO
 	RemoveFile SubCategory = "RemoveFile"
 )
 
 const (
-	AppName      ExtraKey = "AppName"
-	LoggerName   ExtraKey = "Logger"
-	ClientIp     ExtraKey = "ClientIp"
-	HostIp       ExtraKey = "HostIp"
-	Method       ExtraKey = "Method"
-	StatusCode   ExtraKey = "StatusCode"
-	BodySize     ExtraKey = "BodySize"
-	Path         ExtraKey = "Path"
-	Latency      ExtraKey = "Latency"
-	RequestBody  ExtraKey = "RequestBody"
-	ResponseBody ExtraKey = "ResponseBody"
-	ErrorMessage ExtraKey = "ErrorMessage"
+	AppName        ExtraKey = "AppName"
+	LoggerName     ExtraKey = "Logger"
+	ClientIp       ExtraKey = "ClientIp"
+	HostIp         ExtraKey = "HostIp"
+	Method         ExtraKey = "Method"
+	StatusCode     ExtraKey = "StatusCode"
+	BodySize       ExtraKey = "BodySize"
+	Path           ExtraKey = "Path"
+	Latency        ExtraKey = "Latency"
+	RequestBody    ExtraKey = "RequestBody"
+	ResponseBody   ExtraKey = "ResponseBody"
+	ErrorMessage   ExtraKey = "ErrorMessage"
+	TraceId        ExtraKey = "TraceId"
+	SpanId         ExtraKey = "SpanId"
+	RequestId      ExtraKey = "RequestId"
+	GlobalLevel    ExtraKey = "GlobalLevel"
+	CategoryLevels ExtraKey = "CategoryLevels"
+	Repeated       ExtraKey = "Repeated"
 )
@@ -0,0 +1,149 @@
+package logging
+
+import (
+	"base_structure/src/config"
+	"fmt"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Categories lists every log category, so their levels can be set by name.
+var Categories = []Category{General, IO, Internal, Postgres, Redis, Validation, RequestResponse, Prometheus}
+
+// LevelsSnapshot is the global level and the per category overrides in effect.
+type LevelsSnapshot struct {
+	Level      string              `json:"level"`
+	Categories map[Category]string `json:"categories"`
+}
+
+type levelState struct {
+	global     zapcore.Level
+	categories map[Category]zapcore.Level
+	// min is the lowest level any category logs at; the zap cores let through everything from it up.
+	min zapcore.Level
+}
+
+var (
+	levels  atomic.Pointer[levelState]
+	levelMu sync.Mutex
+)
+
+func init() {
+	levels.Store(newLevelState(zapcore.DebugLevel, nil))
+}
+
+func newLevelState(global zapcore.Level, categories map[Category]zapcore.Level) *levelState {
+	st := &levelState{global: global, categories: categories, min: global}
+	for _, lvl := range categories {
+		if lvl < st.min {
+			st.min = lvl
+		}
+	}
+	return st
+}
+
+// enabled reports whether an entry of cat at lvl is written. Entries without a category follow the global level.
+func enabled(cat Category, lvl zapcore.Level) bool {
+	st := levels.Load()
+	if override, ok := st.categories[cat]; ok {
+		return lvl >= override
+	}
+	return lvl >= st.global
+}
+
+// coreEnabler lets every entry some category may need through the zap cores; enabled does the actual filtering.
+var coreEnabler = zap.LevelEnablerFunc(func(lvl zapcore.Level) bool {
+	return lvl >= levels.Load().min
+})
+
+func parseLevel(level string) (zapcore.Level, error) {
+	lvl, ok := zapLogLevelMap[strings.ToLower(strings.TrimSpace(level))]
+	if !ok {
+		return 0, fmt.Errorf("unknown log level %q", level)
+	}
+	return lvl, nil
+}
+
+// parseCategory matches name case-insensitively, since viper lowercases the keys of logger.categories.
+func parseCategory(name string) (Category, error) {
+	for _, cat := range Categories {
+		if strings.EqualFold(string(cat), name) {
+			return cat, nil
+		}
+	}
+	return "", fmt.Errorf("unknown log category %q", name)
+}
+
+// UpdateLevels changes the global level, unless level is empty, and the overrides of the given categories. An empty
+// category level removes its override. Nothing changes if any of them is invalid.
+func UpdateLevels(level string, categories map[string]string) error {
+	levelMu.Lock()
+	defer levelMu.Unlock()
+	st := levels.Load()
+	global := st.global
+	if level != "" {
+		lvl, err := parseLevel(level)
+		if err != nil {
+			return err
+		}
+		global = lvl
+	}
+	overrides := make(map[Category]zapcore.Level, len(st.categories)+len(categories))
+	for cat, lvl := range st.categories {
+		overrides[cat] = lvl
+	}
+	for name, level := range categories {
+		cat, err := parseCategory(name)
+		if err != nil {
+			return err
+		}
+		if level == "" {
+			delete(overrides, cat)
+			continue
+		}
+		if overrides[cat], err = parseLevel(level); err != nil {
+			return fmt.Errorf("category %s: %w", cat, err)
+		}
+	}
+	levels.Store(newLevelState(global, overrides))
+	return nil
+}
+
+// ApplyConfig replaces the global level and every category override with those of cfg. An empty level means debug.
+// Nothing changes if any of them is invalid.
+func ApplyConfig(cfg config.LoggerConfig) error {
+	level := cfg.Level
+	if level == "" {
+		level = "debug"
+	}
+	global, err := parseLevel(level)
+	if err != nil {
+		return err
+	}
+	categories := make(map[Category]zapcore.Level, len(cfg.Categories))
+	for name, level := range cfg.Categories {
+		cat, err := parseCategory(name)
+		if err != nil {
+			return err
+		}
+		if categories[cat], err = parseLevel(level); err != nil {
+			return fmt.Errorf("category %s: %w", cat, err)
+		}
+	}
+	levelMu.Lock()
+	defer levelMu.Unlock()
+	levels.Store(newLevelState(global, categories))
+	return nil
+}
+
+func Levels() LevelsSnapshot {
+	st := levels.Load()
+	snapshot := LevelsSnapshot{Level: st.global.String(), Categories: make(map[Category]string, len(st.categories))}
+	for cat, lvl := range st.categories {
+		snapshot.Categories[cat] = lvl.String()
+	}
+	return snapshot
+}
@@ -1,5 +1,11 @@
 package logging
 
+import (
+	"base_structure/src/pkg/requestid"
+	"context"
+	"go.opentelemetry.io/otel/trace"
+)
+
 func logParamsToZapParams(keys map[ExtraKey]interface{}) []interface{} {
 	params := make([]interface{}, 0)
 	for k, v := range keys {
@@ -8,3 +14,15 @@ func logParamsToZapParams(keys map[ExtraKey]interface{}) []interface{} {
 	}
 	return params
 }
+
+// contextFields returns the request id and the trace and span ids carried by ctx as key/value pairs.
+func contextFields(ctx context.Context) []interface{} {
+	var fields []interface{}
+	if id := requestid.FromContext(ctx); id != "" {
+		fields = append(fields, string(RequestId), id)
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		fields = append(fields, string(TraceId), sc.TraceID().String(), string(SpanId), sc.SpanID().String())
+	}
+	return fields
+}
@@ -2,6 +2,7 @@ package logging
 
 import (
 	"base_structure/src/config"
+	"context"
 	"sync"
 )
 
@@ -25,12 +26,37 @@ type Logger interface {
 	Fatal(cat Category, sub SubCategory, msg string, extra map[ExtraKey]interface{})
 	Fatalf(template string, args ...interface{})
 
+	// WithContext returns a logger that adds the request id and the trace and span ids carried by ctx to every line.
+	WithContext(ctx context.Context) Logger
+
 	Sync() error
 }
 
+var (
+	samplerOnce   sync.Once
+	sharedSampler *sampler
+)
+
+// NewLogger returns the logger.logger backend: zap, slog or zerolog. All of them share the levels, sinks and encoding
+// of LoggerConfig, and one sampler that thins out repeated entries before they reach the backend.
 func NewLogger(cfg *config.Config) Logger {
-	if cfg.Logger.Logger == "zap" {
-		return newZapLogger(cfg)
+	var backend Logger
+	switch cfg.Logger.Logger {
+	case "zap":
+		backend = newZapLogger(cfg)
+	case "slog":
+		backend = newSlogLogger(cfg)
+	case "zerolog":
+		backend = newZerologLogger(cfg)
+	default:
+		panic("logger not supported")
 	}
-	panic("logger not supported")
+	samplerOnce.Do(func() {
+		s, err := newSampler(cfg.Logger)
+		if err != nil {
+			panic(err)
+		}
+		sharedSampler = s
+	})
+	return newSampledLogger(backend, sharedSampler)
 }
@@ -0,0 +1,100 @@
+package logging
+
+import (
+	"base_structure/src/config"
+	"base_structure/src/pkg/requestid"
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func newTestBackends(t *testing.T, buf *bytes.Buffer) map[string]Logger {
+	t.Helper()
+	s, err := newSampler(config.LoggerConfig{})
+	require.NoError(t, err)
+	return map[string]Logger{
+		"slog": newSampledLogger(&slogLogger{logger: slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{
+			AddSource: true,
+			Level:     slog.LevelDebug,
+		}))}, s),
+		"zerolog": newSampledLogger(&zerologLogger{
+			logger: zerolog.New(buf).With().CallerWithSkipFrameCount(zerologCallerSkip).Logger(),
+		}, s),
+	}
+}
+
+func decodeLines(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+	var lines []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var m map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &m))
+		lines = append(lines, m)
+	}
+	return lines
+}
+
+func TestBackendsWriteCategoriesContextAndCaller(t *testing.T) {
+	resetLevels(t)
+	require.NoError(t, ApplyConfig(config.LoggerConfig{Level: "info", Categories: map[string]string{"postgres": "debug"}}))
+	var buf bytes.Buffer
+	for name, logger := range newTestBackends(t, &buf) {
+		t.Run(name, func(t *testing.T) {
+			buf.Reset()
+			ctx := requestid.WithId(context.Background(), "req-1")
+
+			logger.Debug(Redis, Select, "hidden", nil)
+			logger.Debugf("hidden %d", 1)
+			logger.WithContext(ctx).Debug(Postgres, Select, "query", map[ExtraKey]interface{}{Latency: 3})
+			logger.Infof("started %s", "api")
+
+			lines := decodeLines(t, &buf)
+			require.Len(t, lines, 2)
+			assert.Equal(t, "query", messageOf(lines[0]))
+			assert.Equal(t, string(Postgres), lines[0]["Category"])
+			assert.Equal(t, string(Select), lines[0]["SubCategory"])
+			assert.Equal(t, "req-1", lines[0][string(RequestId)])
+			assert.EqualValues(t, 3, lines[0][string(Latency)])
+			assert.Contains(t, callerOf(lines[0]), "logging_backends_test.go")
+			assert.Contains(t, callerOf(lines[1]), "logging_backends_test.go")
+		})
+	}
+}
+
+// messageOf and callerOf read the fields that slog and zerolog name differently.
+func messageOf(line map[string]interface{}) string {
+	if msg, ok := line["msg"].(string); ok {
+		return msg
+	}
+	msg, _ := line["message"].(string)
+	return msg
+}
+
+func callerOf(line map[string]interface{}) string {
+	if caller, ok := line["caller"].(string); ok {
+		return caller
+	}
+	source, _ := line["source"].(map[string]interface{})
+	file, _ := source["file"].(string)
+	return file
+}
+
+func TestNewSinksAndEncodingRejectUnknownNames(t *testing.T) {
+	_, err := newSinks(config.LoggerConfig{Sinks: []string{"stdout", "syslog"}})
+	assert.Error(t, err)
+	_, err = encoding(config.LoggerConfig{Encoding: "xml"})
+	assert.Error(t, err)
+
+	sinks, err := newSinks(config.LoggerConfig{FilePath: t.TempDir() + "/"})
+	require.NoError(t, err)
+	assert.Len(t, sinks, 2)
+}
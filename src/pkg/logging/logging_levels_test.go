@@ -0,0 +1,63 @@
+package logging
+
+import (
+	"base_structure/src/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+	"testing"
+)
+
+func resetLevels(t *testing.T) {
+	t.Helper()
+	saved := levels.Load()
+	t.Cleanup(func() {
+		levels.Store(saved)
+	})
+}
+
+func TestApplyConfigSetsCategoryOverrides(t *testing.T) {
+	resetLevels(t)
+	require.NoError(t, ApplyConfig(config.LoggerConfig{
+		Level:      "info",
+		Categories: map[string]string{"postgres": "debug", "requestresponse": "warn"},
+	}))
+
+	assert.True(t, enabled(Postgres, zapcore.DebugLevel))
+	assert.False(t, enabled(RequestResponse, zapcore.InfoLevel))
+	assert.True(t, enabled(RequestResponse, zapcore.WarnLevel))
+	assert.False(t, enabled(Redis, zapcore.DebugLevel))
+	assert.False(t, enabled("", zapcore.DebugLevel))
+	assert.True(t, coreEnabler.Enabled(zapcore.DebugLevel))
+	assert.Equal(t, LevelsSnapshot{
+		Level:      "info",
+		Categories: map[Category]string{Postgres: "debug", RequestResponse: "warn"},
+	}, Levels())
+}
+
+func TestUpdateLevelsChangesOnlyWhatIsGiven(t *testing.T) {
+	resetLevels(t)
+	require.NoError(t, ApplyConfig(config.LoggerConfig{Level: "warn", Categories: map[string]string{"Redis": "debug"}}))
+
+	require.NoError(t, UpdateLevels("", map[string]string{"Postgres": "error"}))
+	assert.Equal(t, LevelsSnapshot{
+		Level:      "warn",
+		Categories: map[Category]string{Redis: "debug", Postgres: "error"},
+	}, Levels())
+
+	require.NoError(t, UpdateLevels("error", map[string]string{"redis": ""}))
+	assert.Equal(t, LevelsSnapshot{Level: "error", Categories: map[Category]string{Postgres: "error"}}, Levels())
+	assert.False(t, coreEnabler.Enabled(zapcore.WarnLevel))
+}
+
+func TestInvalidLevelsChangeNothing(t *testing.T) {
+	resetLevels(t)
+	require.NoError(t, ApplyConfig(config.LoggerConfig{Level: "info"}))
+	before := Levels()
+
+	assert.Error(t, UpdateLevels("verbose", nil))
+	assert.Error(t, UpdateLevels("debug", map[string]string{"Kafka": "debug"}))
+	assert.Error(t, UpdateLevels("debug", map[string]string{"Postgres": "loud"}))
+	assert.Error(t, ApplyConfig(config.LoggerConfig{Level: "debug", Categories: map[string]string{"redis": "x"}}))
+	assert.Equal(t, before, Levels())
+}
@@ -0,0 +1,82 @@
+package logging
+
+import (
+	"base_structure/src/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func newTestRedactor(t *testing.T, cfg config.RedactionConfig) *Redactor {
+	t.Helper()
+	r, err := NewRedactor(cfg)
+	require.NoError(t, err)
+	return r
+}
+
+func TestRedactorMasksDeniedFieldsAtAnyDepth(t *testing.T) {
+	r := newTestRedactor(t, config.RedactionConfig{})
+
+	body := r.Body("/login", "application/json; charset=utf-8",
+		[]byte(`{"username":"jdoe","Password":"Secret123!","result":{"accessToken":"a.b.c","refreshToken":"d.e.f","accessTokenExpireTime":1700000000000}}`))
+
+	assert.JSONEq(t, `{"username":"jdoe","Password":"***","result":{"accessToken":"***","refreshToken":"***","accessTokenExpireTime":1700000000000}}`, body)
+}
+
+func TestRedactorAppliesRouteRules(t *testing.T) {
+	r := newTestRedactor(t, config.RedactionConfig{
+		Routes: []config.RedactionRouteConfig{{Path: "/login-by-mobile", Fields: []string{"mobileNumber"}}},
+	})
+	body := []byte(`{"mobileNumber":"09121234567","otp":"123456"}`)
+
+	assert.JSONEq(t, `{"mobileNumber":"***","otp":"***"}`, r.Body("/login-by-mobile", "application/json", body))
+	assert.JSONEq(t, `{"mobileNumber":"09121234567","otp":"***"}`, r.Body("/other", "application/json", body))
+}
+
+func TestRedactorMasksMobileNumbersEmailsAndPatterns(t *testing.T) {
+	r := newTestRedactor(t, config.RedactionConfig{
+		Mask:       "[x]",
+		MaskMobile: true,
+		MaskEmail:  true,
+		Patterns:   []string{`IR\d{24}`},
+	})
+
+	assert.Equal(t, "call 0912*****67 or +98912*****67, mail j***@example.com, iban [x]",
+		r.Text("call 09121234567 or +989121234567, mail john.doe@example.com, iban IR123456789012345678901234"))
+	assert.Equal(t, "mobile=۰۹۱۲*****۶۷&code=٠٩١٢*****٦٧ شماره ۰۹۱۲*****۶۷",
+		r.Text("mobile=۰۹۱۲۱۲۳۴۵۶۷&code=٠٩١٢١٢٣٤٥٦٧ شماره ۰۹۱۲۱۲۳۴۵۶۷"))
+	assert.Equal(t, "id 109121234567 and x09121234567", r.Text("id 109121234567 and x09121234567"))
+	assert.JSONEq(t, `{"email":"j***@example.com","password":"[x]"}`,
+		r.Body("/", "application/json", []byte(`{"email":"jane@example.com","password":"p"}`)))
+}
+
+func TestRedactorAllowlistMasksEverythingElse(t *testing.T) {
+	r := newTestRedactor(t, config.RedactionConfig{
+		Mode:        "allowlist",
+		AllowFields: []string{"username", "success"},
+		Routes:      []config.RedactionRouteConfig{{Path: "/register", AllowFields: []string{"firstName"}}},
+	})
+
+	assert.JSONEq(t, `{"username":"jdoe","firstName":"***","nested":{"success":true,"note":"***"},"tags":["***"]}`,
+		r.Body("/", "application/json", []byte(`{"username":"jdoe","firstName":"John","nested":{"success":true,"note":"hi"},"tags":["a"]}`)))
+	assert.JSONEq(t, `{"username":"jdoe","firstName":"John"}`,
+		r.Body("/register", "application/json", []byte(`{"username":"jdoe","firstName":"John"}`)))
+	assert.JSONEq(t, `{"title":"***","success":false}`,
+		r.Body("/", "application/problem+json", []byte(`{"title":"Conflict","success":false}`)))
+	assert.Equal(t, redactedBody, r.Body("/", "text/plain", []byte("free text")))
+}
+
+func TestRedactorHandlesFormsAndInvalidJson(t *testing.T) {
+	r := newTestRedactor(t, config.RedactionConfig{MaskMobile: true})
+
+	assert.Equal(t, "otp=%2A%2A%2A&username=jdoe",
+		r.Body("/", "application/x-www-form-urlencoded", []byte("username=jdoe&otp=123456")))
+	assert.Equal(t, `{"mobile":"0912*****67"`, r.Body("/", "application/json", []byte(`{"mobile":"09121234567"`)))
+}
+
+func TestNewRedactorRejectsBadConfig(t *testing.T) {
+	_, err := NewRedactor(config.RedactionConfig{Mode: "blocklist"})
+	assert.Error(t, err)
+	_, err = NewRedactor(config.RedactionConfig{Patterns: []string{"("}})
+	assert.Error(t, err)
+}
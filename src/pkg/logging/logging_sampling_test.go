@@ -0,0 +1,146 @@
+package logging
+
+import (
+	"base_structure/src/config"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+type entry struct {
+	level   string
+	cat     Category
+	msg     string
+	extra   map[ExtraKey]interface{}
+	request bool
+}
+
+// recordingLogger keeps the entries that get past sampledLogger, marking those logged with a request context.
+type recordingLogger struct {
+	entries *[]entry
+	request bool
+}
+
+func (l recordingLogger) add(level string, cat Category, msg string, extra map[ExtraKey]interface{}) {
+	*l.entries = append(*l.entries, entry{level: level, cat: cat, msg: msg, extra: extra, request: l.request})
+}
+
+func (l recordingLogger) Init() {}
+func (l recordingLogger) Debug(cat Category, _ SubCategory, msg string, extra map[ExtraKey]interface{}) {
+	l.add("debug", cat, msg, extra)
+}
+func (l recordingLogger) Debugf(template string, _ ...interface{}) { l.add("debug", "", template, nil) }
+func (l recordingLogger) Info(cat Category, _ SubCategory, msg string, extra map[ExtraKey]interface{}) {
+	l.add("info", cat, msg, extra)
+}
+func (l recordingLogger) Infof(template string, _ ...interface{}) { l.add("info", "", template, nil) }
+func (l recordingLogger) Warn(cat Category, _ SubCategory, msg string, extra map[ExtraKey]interface{}) {
+	l.add("warn", cat, msg, extra)
+}
+func (l recordingLogger) Warnf(template string, _ ...interface{}) { l.add("warn", "", template, nil) }
+func (l recordingLogger) Error(cat Category, _ SubCategory, msg string, extra map[ExtraKey]interface{}) {
+	l.add("error", cat, msg, extra)
+}
+func (l recordingLogger) Errorf(template string, _ ...interface{}) { l.add("error", "", template, nil) }
+func (l recordingLogger) Fatal(cat Category, _ SubCategory, msg string, extra map[ExtraKey]interface{}) {
+	l.add("fatal", cat, msg, extra)
+}
+func (l recordingLogger) Fatalf(template string, _ ...interface{}) { l.add("fatal", "", template, nil) }
+func (l recordingLogger) WithContext(context.Context) Logger {
+	return recordingLogger{entries: l.entries, request: true}
+}
+func (l recordingLogger) Sync() error { return nil }
+
+type fakeClock struct {
+	t time.Time
+}
+
+func (c *fakeClock) now() time.Time {
+	return c.t
+}
+
+func newSampledTestLogger(t *testing.T, cfg config.LoggerConfig) (*sampledLogger, *[]entry, *fakeClock) {
+	t.Helper()
+	resetLevels(t)
+	require.NoError(t, ApplyConfig(config.LoggerConfig{Level: "debug"}))
+	s, err := newSampler(cfg)
+	require.NoError(t, err)
+	clock := &fakeClock{t: time.Unix(1700000000, 0)}
+	s.now = clock.now
+	entries := &[]entry{}
+	return newSampledLogger(recordingLogger{entries: entries}, s), entries, clock
+}
+
+func TestSamplingKeepsFirstAndEveryNthPerInterval(t *testing.T) {
+	logger, entries, clock := newSampledTestLogger(t, config.LoggerConfig{Sampling: config.LogSamplingConfig{
+		Interval: 1,
+		Levels:   map[string]config.LogSamplingRule{"info": {First: 2, Thereafter: 3}},
+	}})
+
+	for i := 0; i < 8; i++ {
+		logger.Info(General, StartUp, "tick", nil)
+	}
+	assert.Len(t, *entries, 4) // 1, 2, 5 and 8
+
+	clock.t = clock.t.Add(2 * time.Second)
+	logger.Info(General, StartUp, "tick", nil)
+	logger.Warn(General, StartUp, "tick", nil)
+	assert.Len(t, *entries, 6)
+}
+
+func TestCategoryRulesOverrideLevelRulesAndErrorsAreKept(t *testing.T) {
+	logger, entries, _ := newSampledTestLogger(t, config.LoggerConfig{Sampling: config.LogSamplingConfig{
+		Levels:     map[string]config.LogSamplingRule{"info": {First: 100}},
+		Categories: map[string]config.LogSamplingRule{"requestresponse": {First: 1}},
+	}})
+
+	for i := 0; i < 5; i++ {
+		logger.Info(RequestResponse, Api, "", nil)
+		logger.Error(RequestResponse, Api, "", nil)
+		logger.Info(Postgres, Select, "query", nil)
+	}
+
+	counts := map[string]int{}
+	for _, e := range *entries {
+		counts[string(e.cat)+"/"+e.level]++
+	}
+	assert.Equal(t, map[string]int{"RequestResponse/info": 1, "RequestResponse/error": 5, "Postgres/info": 5}, counts)
+}
+
+func TestRepeatedErrorsAreCountedWithinTheWindow(t *testing.T) {
+	logger, entries, clock := newSampledTestLogger(t, config.LoggerConfig{DedupWindow: 10})
+
+	for i := 0; i < 1000; i++ {
+		logger.Error(Redis, Select, "connection refused", nil)
+	}
+	logger.Error(Postgres, Select, "timeout", nil)
+	require.Len(t, *entries, 2)
+
+	clock.t = clock.t.Add(11 * time.Second)
+	logger.Error(Redis, Select, "connection refused", nil)
+	require.Len(t, *entries, 3)
+	assert.Equal(t, 999, (*entries)[2].extra[Repeated])
+
+	for i := 0; i < 4; i++ {
+		logger.Error(Postgres, Select, "timeout", nil)
+	}
+	clock.t = clock.t.Add(11 * time.Second)
+	logger.WithContext(context.Background()).Error(General, StartUp, "other", nil)
+	require.Len(t, *entries, 6)
+	assert.Equal(t, entry{level: "error", cat: Postgres, msg: "timeout", extra: map[ExtraKey]interface{}{Repeated: 3}}, (*entries)[4],
+		"the summary is not attributed to the request that ended the window")
+	assert.True(t, (*entries)[5].request)
+}
+
+func TestNewSamplerRejectsUnknownNames(t *testing.T) {
+	_, err := newSampler(config.LoggerConfig{Sampling: config.LogSamplingConfig{
+		Levels: map[string]config.LogSamplingRule{"verbose": {First: 1}},
+	}})
+	assert.Error(t, err)
+	_, err = newSampler(config.LoggerConfig{Sampling: config.LogSamplingConfig{
+		Categories: map[string]config.LogSamplingRule{"kafka": {First: 1}},
+	}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,252 @@
+package logging
+
+import (
+	"base_structure/src/config"
+	"base_structure/src/pkg/metrics"
+	"context"
+	"fmt"
+	"go.uber.org/zap/zapcore"
+	"sync"
+	"time"
+)
+
+const defaultSamplingInterval = time.Second
+
+type sampleKey struct {
+	cat Category
+	lvl zapcore.Level
+	msg string
+}
+
+type dedupKey struct {
+	cat Category
+	sub SubCategory
+	msg string
+}
+
+type dedupEntry struct {
+	until      time.Time
+	suppressed int
+}
+
+// repeatedError is an error whose copies were dropped during a dedup window that has ended.
+type repeatedError struct {
+	dedupKey
+	count int
+}
+
+// sampler drops entries under the sampling rules and repeated errors within the dedup window. Counters are kept per
+// interval and dropped with it, so unique messages don't pile up.
+type sampler struct {
+	mu         sync.Mutex
+	now        func() time.Time
+	interval   time.Duration
+	levels     map[zapcore.Level]config.LogSamplingRule
+	categories map[Category]config.LogSamplingRule
+	tickEnd    time.Time
+	counts     map[sampleKey]int
+	window     time.Duration
+	sweepAt    time.Time
+	seen       map[dedupKey]*dedupEntry
+}
+
+func newSampler(cfg config.LoggerConfig) (*sampler, error) {
+	s := &sampler{
+		now:        time.Now,
+		interval:   cfg.Sampling.Interval * time.Second,
+		levels:     make(map[zapcore.Level]config.LogSamplingRule, len(cfg.Sampling.Levels)),
+		categories: make(map[Category]config.LogSamplingRule, len(cfg.Sampling.Categories)),
+		counts:     map[sampleKey]int{},
+		window:     cfg.DedupWindow * time.Second,
+		seen:       map[dedupKey]*dedupEntry{},
+	}
+	if s.interval <= 0 {
+		s.interval = defaultSamplingInterval
+	}
+	for name, rule := range cfg.Sampling.Levels {
+		lvl, err := parseLevel(name)
+		if err != nil {
+			return nil, fmt.Errorf("log sampling: %w", err)
+		}
+		s.levels[lvl] = rule
+	}
+	for name, rule := range cfg.Sampling.Categories {
+		cat, err := parseCategory(name)
+		if err != nil {
+			return nil, fmt.Errorf("log sampling: %w", err)
+		}
+		s.categories[cat] = rule
+	}
+	return s, nil
+}
+
+// sample reports whether an entry below the error level is kept.
+func (s *sampler) sample(cat Category, lvl zapcore.Level, msg string) bool {
+	if lvl >= zapcore.ErrorLevel {
+		return true
+	}
+	rule, ok := s.categories[cat]
+	if !ok {
+		rule, ok = s.levels[lvl]
+	}
+	if !ok {
+		return true
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	if now.After(s.tickEnd) {
+		s.counts = map[sampleKey]int{}
+		s.tickEnd = now.Add(s.interval)
+	}
+	k := sampleKey{cat: cat, lvl: lvl, msg: msg}
+	n := s.counts[k] + 1
+	s.counts[k] = n
+	if n <= rule.First {
+		return true
+	}
+	return rule.Thereafter > 0 && (n-rule.First)%rule.Thereafter == 0
+}
+
+// dedup reports whether an error is kept and how many copies of it were dropped in its previous window. It also
+// returns the other errors whose window has ended with dropped copies, so their counts are not lost when they stop.
+// Request lines are left alone: each one is a separate request even though they share an empty message.
+func (s *sampler) dedup(cat Category, sub SubCategory, msg string) (bool, int, []repeatedError) {
+	if s.window <= 0 || cat == RequestResponse {
+		return true, 0, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	k := dedupKey{cat: cat, sub: sub, msg: msg}
+	var ended []repeatedError
+	if now.After(s.sweepAt) {
+		for other, e := range s.seen {
+			if other == k || !now.After(e.until) {
+				continue
+			}
+			if e.suppressed > 0 {
+				ended = append(ended, repeatedError{dedupKey: other, count: e.suppressed})
+			}
+			delete(s.seen, other)
+		}
+		s.sweepAt = now.Add(s.window)
+	}
+	e, ok := s.seen[k]
+	if ok && !now.After(e.until) {
+		e.suppressed++
+		return false, 0, ended
+	}
+	repeated := 0
+	if ok {
+		repeated = e.suppressed
+	}
+	s.seen[k] = &dedupEntry{until: now.Add(s.window)}
+	return true, repeated, ended
+}
+
+// sampledLogger applies the sampler in front of a backend. WithContext keeps sharing the same sampler. base is the
+// backend without request fields, which reports the repeated errors of ended windows: they don't belong to the request
+// that happens to end them.
+type sampledLogger struct {
+	next    Logger
+	base    Logger
+	sampler *sampler
+}
+
+func newSampledLogger(backend Logger, s *sampler) *sampledLogger {
+	return &sampledLogger{next: backend, base: backend, sampler: s}
+}
+
+func (l *sampledLogger) Init() { l.next.Init() }
+
+func (l *sampledLogger) keep(cat Category, lvl zapcore.Level, msg string) bool {
+	if !enabled(cat, lvl) {
+		return false
+	}
+	if !l.sampler.sample(cat, lvl, msg) {
+		metrics.LogEntriesDropped.WithLabelValues("sampled").Inc()
+		return false
+	}
+	return true
+}
+
+// keepError deduplicates an error, adding the number of dropped copies to extra. Errors whose window has ended are
+// reported first.
+func (l *sampledLogger) keepError(cat Category, sub SubCategory, msg string, extra map[ExtraKey]interface{}) (map[ExtraKey]interface{}, bool) {
+	if !enabled(cat, zapcore.ErrorLevel) {
+		return nil, false
+	}
+	keep, repeated, ended := l.sampler.dedup(cat, sub, msg)
+	for _, r := range ended {
+		l.base.Error(r.cat, r.sub, r.msg, map[ExtraKey]interface{}{Repeated: r.count})
+	}
+	if !keep {
+		metrics.LogEntriesDropped.WithLabelValues("duplicate").Inc()
+		return nil, false
+	}
+	if repeated > 0 {
+		if extra == nil {
+			extra = map[ExtraKey]interface{}{}
+		}
+		extra[Repeated] = repeated
+	}
+	return extra, true
+}
+
+func (l *sampledLogger) Debug(cat Category, sub SubCategory, msg string, extra map[ExtraKey]interface{}) {
+	if l.keep(cat, zapcore.DebugLevel, msg) {
+		l.next.Debug(cat, sub, msg, extra)
+	}
+}
+func (l *sampledLogger) Debugf(template string, args ...interface{}) {
+	if l.keep("", zapcore.DebugLevel, template) {
+		l.next.Debugf(template, args...)
+	}
+}
+
+func (l *sampledLogger) Info(cat Category, sub SubCategory, msg string, extra map[ExtraKey]interface{}) {
+	if l.keep(cat, zapcore.InfoLevel, msg) {
+		l.next.Info(cat, sub, msg, extra)
+	}
+}
+func (l *sampledLogger) Infof(template string, args ...interface{}) {
+	if l.keep("", zapcore.InfoLevel, template) {
+		l.next.Infof(template, args...)
+	}
+}
+
+func (l *sampledLogger) Warn(cat Category, sub SubCategory, msg string, extra map[ExtraKey]interface{}) {
+	if l.keep(cat, zapcore.WarnLevel, msg) {
+		l.next.Warn(cat, sub, msg, extra)
+	}
+}
+func (l *sampledLogger) Warnf(template string, args ...interface{}) {
+	if l.keep("", zapcore.WarnLevel, template) {
+		l.next.Warnf(template, args...)
+	}
+}
+
+func (l *sampledLogger) Error(cat Category, sub SubCategory, msg string, extra map[ExtraKey]interface{}) {
+	if extra, ok := l.keepError(cat, sub, msg, extra); ok {
+		l.next.Error(cat, sub, msg, extra)
+	}
+}
+func (l *sampledLogger) Errorf(template string, args ...interface{}) {
+	if _, ok := l.keepError("", "", fmt.Sprintf(template, args...), nil); ok {
+		l.next.Errorf(template, args...)
+	}
+}
+
+func (l *sampledLogger) Fatal(cat Category, sub SubCategory, msg string, extra map[ExtraKey]interface{}) {
+	l.next.Fatal(cat, sub, msg, extra)
+}
+func (l *sampledLogger) Fatalf(template string, args ...interface{}) {
+	l.next.Fatalf(template, args...)
+}
+
+func (l *sampledLogger) WithContext(ctx context.Context) Logger {
+	return &sampledLogger{next: l.next.WithContext(ctx), base: l.base, sampler: l.sampler}
+}
+
+func (l *sampledLogger) Sync() error { return l.next.Sync() }
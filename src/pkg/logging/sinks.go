@@ -0,0 +1,77 @@
+package logging
+
+import (
+	"base_structure/src/config"
+	"fmt"
+	"gopkg.in/natefinch/lumberjack.v2"
+	"io"
+	"os"
+	"time"
+)
+
+const (
+	StdoutSink = "stdout"
+	FileSink   = "file"
+
+	JsonEncoding    = "json"
+	ConsoleEncoding = "console"
+
+	defaultMaxSize    = 10 // MB
+	defaultMaxBackups = 30
+	defaultMaxAge     = 14 // days
+)
+
+type sink struct {
+	name   string
+	writer io.Writer
+}
+
+// newSinks opens the writers named by logger.sinks, stdout and file when none are configured.
+func newSinks(cfg config.LoggerConfig) ([]sink, error) {
+	names := cfg.Sinks
+	if len(names) == 0 {
+		names = []string{StdoutSink, FileSink}
+	}
+	sinks := make([]sink, 0, len(names))
+	for _, name := range names {
+		switch name {
+		case StdoutSink:
+			sinks = append(sinks, sink{name: name, writer: os.Stdout})
+		case FileSink:
+			sinks = append(sinks, sink{name: name, writer: newFileWriter(cfg)})
+		default:
+			return nil, fmt.Errorf("unknown log sink %q", name)
+		}
+	}
+	return sinks, nil
+}
+
+func newFileWriter(cfg config.LoggerConfig) io.Writer {
+	return &lumberjack.Logger{
+		Filename:   fmt.Sprintf("%s%s.%s", cfg.FilePath, time.Now().Format("2006-01-02"), "log"),
+		MaxSize:    orDefault(cfg.MaxSize, defaultMaxSize),
+		MaxBackups: orDefault(cfg.MaxBackups, defaultMaxBackups),
+		MaxAge:     orDefault(cfg.MaxAge, defaultMaxAge),
+		Compress:   true,
+		LocalTime:  true,
+	}
+}
+
+// encoding returns logger.encoding, json when it isn't set.
+func encoding(cfg config.LoggerConfig) (string, error) {
+	switch cfg.Encoding {
+	case "", JsonEncoding:
+		return JsonEncoding, nil
+	case ConsoleEncoding:
+		return ConsoleEncoding, nil
+	default:
+		return "", fmt.Errorf("unknown log encoding %q", cfg.Encoding)
+	}
+}
+
+func orDefault(v int, def int) int {
+	if v <= 0 {
+		return def
+	}
+	return v
+}
@@ -0,0 +1,153 @@
+package logging
+
+import (
+	"base_structure/src/config"
+	"context"
+	"fmt"
+	"go.uber.org/zap/zapcore"
+	"io"
+	"log/slog"
+	"os"
+	"runtime"
+	"time"
+)
+
+// slogFatalLevel is above slog.LevelError; slog has no fatal level of its own.
+const slogFatalLevel = slog.Level(12)
+
+var slogSingleLogger *slog.Logger
+
+type slogLogger struct {
+	cfg    *config.Config
+	logger *slog.Logger
+}
+
+func newSlogLogger(cfg *config.Config) *slogLogger {
+	l := &slogLogger{cfg: cfg}
+	l.Init()
+	return l
+}
+
+func (l *slogLogger) Init() {
+	once.Do(func() {
+		levelErr := ApplyConfig(l.cfg.Logger)
+		handler, err := newSlogHandler(l.cfg.Logger)
+		if err != nil {
+			panic(err)
+		}
+		slogSingleLogger = slog.New(handler).With(string(AppName), os.Getenv("APP_NAME"), string(LoggerName), "SlogLog")
+		if levelErr != nil {
+			slogSingleLogger.Warn(levelErr.Error(), prepareLogInfo(nil, Internal, StartUp)...)
+		}
+	})
+	l.logger = slogSingleLogger
+}
+
+// newSlogHandler writes to every sink with the JSON handler, or the text handler for the console encoding. It lets
+// every level through; slogLogger does the filtering.
+func newSlogHandler(cfg config.LoggerConfig) (slog.Handler, error) {
+	enc, err := encoding(cfg)
+	if err != nil {
+		return nil, err
+	}
+	sinks, err := newSinks(cfg)
+	if err != nil {
+		return nil, err
+	}
+	writers := make([]io.Writer, 0, len(sinks))
+	for _, s := range sinks {
+		writers = append(writers, s.writer)
+	}
+	opts := &slog.HandlerOptions{
+		AddSource:   true,
+		Level:       slog.LevelDebug,
+		ReplaceAttr: replaceSlogLevel,
+	}
+	if enc == ConsoleEncoding {
+		return slog.NewTextHandler(io.MultiWriter(writers...), opts), nil
+	}
+	return slog.NewJSONHandler(io.MultiWriter(writers...), opts), nil
+}
+
+func replaceSlogLevel(_ []string, a slog.Attr) slog.Attr {
+	if a.Key == slog.LevelKey && a.Value.Any() == slogFatalLevel {
+		return slog.String(slog.LevelKey, "FATAL")
+	}
+	return a
+}
+
+// log writes a record whose source is the caller of the Logger method, past sampledLogger.
+func (l *slogLogger) log(level slog.Level, msg string, args []interface{}) {
+	var pcs [1]uintptr
+	runtime.Callers(4, pcs[:])
+	r := slog.NewRecord(time.Now(), level, msg, pcs[0])
+	r.Add(args...)
+	_ = l.logger.Handler().Handle(context.Background(), r)
+}
+
+func (l *slogLogger) Debug(cat Category, sub SubCategory, msg string, extra map[ExtraKey]interface{}) {
+	if enabled(cat, zapcore.DebugLevel) {
+		l.log(slog.LevelDebug, msg, prepareLogInfo(extra, cat, sub))
+	}
+}
+func (l *slogLogger) Debugf(template string, args ...interface{}) {
+	if enabled("", zapcore.DebugLevel) {
+		l.log(slog.LevelDebug, fmt.Sprintf(template, args...), nil)
+	}
+}
+
+func (l *slogLogger) Info(cat Category, sub SubCategory, msg string, extra map[ExtraKey]interface{}) {
+	if enabled(cat, zapcore.InfoLevel) {
+		l.log(slog.LevelInfo, msg, prepareLogInfo(extra, cat, sub))
+	}
+}
+func (l *slogLogger) Infof(template string, args ...interface{}) {
+	if enabled("", zapcore.InfoLevel) {
+		l.log(slog.LevelInfo, fmt.Sprintf(template, args...), nil)
+	}
+}
+
+func (l *slogLogger) Warn(cat Category, sub SubCategory, msg string, extra map[ExtraKey]interface{}) {
+	if enabled(cat, zapcore.WarnLevel) {
+		l.log(slog.LevelWarn, msg, prepareLogInfo(extra, cat, sub))
+	}
+}
+func (l *slogLogger) Warnf(template string, args ...interface{}) {
+	if enabled("", zapcore.WarnLevel) {
+		l.log(slog.LevelWarn, fmt.Sprintf(template, args...), nil)
+	}
+}
+
+func (l *slogLogger) Error(cat Category, sub SubCategory, msg string, extra map[ExtraKey]interface{}) {
+	if enabled(cat, zapcore.ErrorLevel) {
+		l.log(slog.LevelError, msg, prepareLogInfo(extra, cat, sub))
+	}
+}
+func (l *slogLogger) Errorf(template string, args ...interface{}) {
+	if enabled("", zapcore.ErrorLevel) {
+		l.log(slog.LevelError, fmt.Sprintf(template, args...), nil)
+	}
+}
+
+func (l *slogLogger) Fatal(cat Category, sub SubCategory, msg string, extra map[ExtraKey]interface{}) {
+	l.log(slogFatalLevel, msg, prepareLogInfo(extra, cat, sub))
+	os.Exit(1)
+}
+func (l *slogLogger) Fatalf(template string, args ...interface{}) {
+	l.log(slogFatalLevel, fmt.Sprintf(template, args...), nil)
+	os.Exit(1)
+}
+
+func (l *slogLogger) WithContext(ctx context.Context) Logger {
+	fields := contextFields(ctx)
+	if len(fields) == 0 {
+		return l
+	}
+	return &slogLogger{
+		cfg:    l.cfg,
+		logger: l.logger.With(fields...),
+	}
+}
+
+// Sync has nothing to flush: slog handlers write every record through.
+func (l *slogLogger) Sync() error { return nil }
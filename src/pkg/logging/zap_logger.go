@@ -2,17 +2,14 @@ package logging
 
 import (
 	"base_structure/src/config"
-	"fmt"
+	"context"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
-	"gopkg.in/natefinch/lumberjack.v2"
 	"os"
-	"time"
 )
 
 var (
 	zapSingleLogger *zap.SugaredLogger
-	atomicLevel     zap.AtomicLevel
 	zapLogLevelMap  = map[string]zapcore.Level{
 		"debug": zapcore.DebugLevel,
 		"info":  zapcore.InfoLevel,
@@ -44,72 +41,120 @@ func prepareLogInfo(extra map[ExtraKey]interface{}, cat Category, sub SubCategor
 
 func (l *zapLogger) Init() {
 	once.Do(func() {
-		atomicLevel = zap.NewAtomicLevelAt(l.getLogLevel())
-		fileName := fmt.Sprintf("%s%s.%s", l.cfg.Logger.FilePath, time.Now().Format("2006-01-02"), "log")
-		fileCore := zapcore.NewCore(
-			zapcore.NewJSONEncoder(prodEncoderCfg()),
-			zapcore.AddSync(&lumberjack.Logger{
-				Filename:   fileName,
-				MaxSize:    10, // MB
-				MaxBackups: 30,
-				MaxAge:     14, // days
-				Compress:   true,
-				LocalTime:  true,
-			}),
-			atomicLevel,
-		)
-		consoleCore := zapcore.NewCore(
-			zapcore.NewJSONEncoder(prodEncoderCfg()),
-			zapcore.AddSync(os.Stdout),
-			atomicLevel,
-		)
-		core := zapcore.NewTee(fileCore, consoleCore)
+		levelErr := ApplyConfig(l.cfg.Logger)
+		core, err := newZapCore(l.cfg.Logger)
+		if err != nil {
+			panic(err)
+		}
 		zl := zap.New(core,
 			zap.AddCaller(),
-			zap.AddCallerSkip(1),
+			zap.AddCallerSkip(2),
 			zap.AddStacktrace(zapcore.ErrorLevel),
 		).Sugar()
 		zapSingleLogger = zl.With(string(AppName), os.Getenv("APP_NAME"), string(LoggerName), "ZapLog")
+		if levelErr != nil {
+			zapSingleLogger.Warnw(levelErr.Error(), prepareLogInfo(nil, Internal, StartUp)...)
+		}
 	})
 	l.logger = zapSingleLogger
 }
 
+// newZapCore tees one core per sink. Levels are filtered by coreEnabler and the category checks of zapLogger.
+func newZapCore(cfg config.LoggerConfig) (zapcore.Core, error) {
+	enc, err := encoding(cfg)
+	if err != nil {
+		return nil, err
+	}
+	sinks, err := newSinks(cfg)
+	if err != nil {
+		return nil, err
+	}
+	cores := make([]zapcore.Core, 0, len(sinks))
+	for _, s := range sinks {
+		cores = append(cores, zapcore.NewCore(zapEncoder(enc, s.name == StdoutSink), zapcore.AddSync(s.writer), coreEnabler))
+	}
+	return zapcore.NewTee(cores...), nil
+}
+
+// zapEncoder returns the JSON encoder, or for the console encoding a human-readable one, colored on a terminal.
+func zapEncoder(encoding string, color bool) zapcore.Encoder {
+	if encoding != ConsoleEncoding {
+		return zapcore.NewJSONEncoder(prodEncoderCfg())
+	}
+	enc := zap.NewDevelopmentEncoderConfig()
+	enc.EncodeTime = zapcore.ISO8601TimeEncoder
+	if color {
+		enc.EncodeLevel = zapcore.CapitalColorLevelEncoder
+	}
+	return zapcore.NewConsoleEncoder(enc)
+}
+
 func prodEncoderCfg() zapcore.EncoderConfig {
 	enc := zap.NewProductionEncoderConfig()
 	enc.EncodeTime = zapcore.ISO8601TimeEncoder
 	return enc
 }
 
-func (l *zapLogger) getLogLevel() zapcore.Level {
-	if lvl, ok := zapLogLevelMap[l.cfg.Logger.Level]; ok {
-		return lvl
-	}
-	return zapcore.DebugLevel
-}
+// The level checks below come before prepareLogInfo, so entries filtered out by their category cost nothing.
 
 func (l *zapLogger) Debug(cat Category, sub SubCategory, msg string, extra map[ExtraKey]interface{}) {
-	l.logger.Debugw(msg, prepareLogInfo(extra, cat, sub)...)
+	if enabled(cat, zapcore.DebugLevel) {
+		l.logger.Debugw(msg, prepareLogInfo(extra, cat, sub)...)
+	}
+}
+func (l *zapLogger) Debugf(template string, args ...interface{}) {
+	if enabled("", zapcore.DebugLevel) {
+		l.logger.Debugf(template, args...)
+	}
 }
-func (l *zapLogger) Debugf(template string, args ...interface{}) { l.logger.Debugf(template, args...) }
 
 func (l *zapLogger) Info(cat Category, sub SubCategory, msg string, extra map[ExtraKey]interface{}) {
-	l.logger.Infow(msg, prepareLogInfo(extra, cat, sub)...)
+	if enabled(cat, zapcore.InfoLevel) {
+		l.logger.Infow(msg, prepareLogInfo(extra, cat, sub)...)
+	}
+}
+func (l *zapLogger) Infof(template string, args ...interface{}) {
+	if enabled("", zapcore.InfoLevel) {
+		l.logger.Infof(template, args...)
+	}
 }
-func (l *zapLogger) Infof(template string, args ...interface{}) { l.logger.Infof(template, args...) }
 
 func (l *zapLogger) Warn(cat Category, sub SubCategory, msg string, extra map[ExtraKey]interface{}) {
-	l.logger.Warnw(msg, prepareLogInfo(extra, cat, sub)...)
+	if enabled(cat, zapcore.WarnLevel) {
+		l.logger.Warnw(msg, prepareLogInfo(extra, cat, sub)...)
+	}
+}
+func (l *zapLogger) Warnf(template string, args ...interface{}) {
+	if enabled("", zapcore.WarnLevel) {
+		l.logger.Warnf(template, args...)
+	}
 }
-func (l *zapLogger) Warnf(template string, args ...interface{}) { l.logger.Warnf(template, args...) }
 
 func (l *zapLogger) Error(cat Category, sub SubCategory, msg string, extra map[ExtraKey]interface{}) {
-	l.logger.Errorw(msg, prepareLogInfo(extra, cat, sub)...)
+	if enabled(cat, zapcore.ErrorLevel) {
+		l.logger.Errorw(msg, prepareLogInfo(extra, cat, sub)...)
+	}
+}
+func (l *zapLogger) Errorf(template string, args ...interface{}) {
+	if enabled("", zapcore.ErrorLevel) {
+		l.logger.Errorf(template, args...)
+	}
 }
-func (l *zapLogger) Errorf(template string, args ...interface{}) { l.logger.Errorf(template, args...) }
 
 func (l *zapLogger) Fatal(cat Category, sub SubCategory, msg string, extra map[ExtraKey]interface{}) {
 	l.logger.Fatalw(msg, prepareLogInfo(extra, cat, sub)...)
 }
 func (l *zapLogger) Fatalf(template string, args ...interface{}) { l.logger.Fatalf(template, args...) }
 
+func (l *zapLogger) WithContext(ctx context.Context) Logger {
+	fields := contextFields(ctx)
+	if len(fields) == 0 {
+		return l
+	}
+	return &zapLogger{
+		cfg:    l.cfg,
+		logger: l.logger.With(fields...),
+	}
+}
+
 func (l *zapLogger) Sync() error { return l.logger.Sync() }
@@ -0,0 +1,137 @@
+package logging
+
+import (
+	"base_structure/src/config"
+	"context"
+	"fmt"
+	"github.com/rs/zerolog"
+	"go.uber.org/zap/zapcore"
+	"io"
+	"os"
+)
+
+// zerologCallerSkip skips log, the Logger method and sampledLogger, so the caller field points at the code that logged.
+const zerologCallerSkip = 5
+
+var zerologSingleLogger zerolog.Logger
+
+type zerologLogger struct {
+	cfg    *config.Config
+	logger zerolog.Logger
+}
+
+func newZerologLogger(cfg *config.Config) *zerologLogger {
+	l := &zerologLogger{cfg: cfg}
+	l.Init()
+	return l
+}
+
+func (l *zerologLogger) Init() {
+	once.Do(func() {
+		levelErr := ApplyConfig(l.cfg.Logger)
+		w, err := newZerologWriter(l.cfg.Logger)
+		if err != nil {
+			panic(err)
+		}
+		zerologSingleLogger = zerolog.New(w).With().
+			Timestamp().
+			CallerWithSkipFrameCount(zerologCallerSkip).
+			Str(string(AppName), os.Getenv("APP_NAME")).
+			Str(string(LoggerName), "ZerologLog").
+			Logger()
+		if levelErr != nil {
+			zerologSingleLogger.Warn().Fields(prepareLogInfo(nil, Internal, StartUp)).Msg(levelErr.Error())
+		}
+	})
+	l.logger = zerologSingleLogger
+}
+
+// newZerologWriter writes JSON to every sink, or for the console encoding human-readable lines, colored on stdout.
+func newZerologWriter(cfg config.LoggerConfig) (io.Writer, error) {
+	enc, err := encoding(cfg)
+	if err != nil {
+		return nil, err
+	}
+	sinks, err := newSinks(cfg)
+	if err != nil {
+		return nil, err
+	}
+	writers := make([]io.Writer, 0, len(sinks))
+	for _, s := range sinks {
+		w := s.writer
+		if enc == ConsoleEncoding {
+			w = zerolog.ConsoleWriter{Out: s.writer, NoColor: s.name != StdoutSink}
+		}
+		writers = append(writers, w)
+	}
+	return zerolog.MultiLevelWriter(writers...), nil
+}
+
+func (l *zerologLogger) log(e *zerolog.Event, msg string, fields []interface{}) {
+	e.Fields(fields).Msg(msg)
+}
+
+func (l *zerologLogger) Debug(cat Category, sub SubCategory, msg string, extra map[ExtraKey]interface{}) {
+	if enabled(cat, zapcore.DebugLevel) {
+		l.log(l.logger.Debug(), msg, prepareLogInfo(extra, cat, sub))
+	}
+}
+func (l *zerologLogger) Debugf(template string, args ...interface{}) {
+	if enabled("", zapcore.DebugLevel) {
+		l.log(l.logger.Debug(), fmt.Sprintf(template, args...), nil)
+	}
+}
+
+func (l *zerologLogger) Info(cat Category, sub SubCategory, msg string, extra map[ExtraKey]interface{}) {
+	if enabled(cat, zapcore.InfoLevel) {
+		l.log(l.logger.Info(), msg, prepareLogInfo(extra, cat, sub))
+	}
+}
+func (l *zerologLogger) Infof(template string, args ...interface{}) {
+	if enabled("", zapcore.InfoLevel) {
+		l.log(l.logger.Info(), fmt.Sprintf(template, args...), nil)
+	}
+}
+
+func (l *zerologLogger) Warn(cat Category, sub SubCategory, msg string, extra map[ExtraKey]interface{}) {
+	if enabled(cat, zapcore.WarnLevel) {
+		l.log(l.logger.Warn(), msg, prepareLogInfo(extra, cat, sub))
+	}
+}
+func (l *zerologLogger) Warnf(template string, args ...interface{}) {
+	if enabled("", zapcore.WarnLevel) {
+		l.log(l.logger.Warn(), fmt.Sprintf(template, args...), nil)
+	}
+}
+
+func (l *zerologLogger) Error(cat Category, sub SubCategory, msg string, extra map[ExtraKey]interface{}) {
+	if enabled(cat, zapcore.ErrorLevel) {
+		l.log(l.logger.Error(), msg, prepareLogInfo(extra, cat, sub))
+	}
+}
+func (l *zerologLogger) Errorf(template string, args ...interface{}) {
+	if enabled("", zapcore.ErrorLevel) {
+		l.log(l.logger.Error(), fmt.Sprintf(template, args...), nil)
+	}
+}
+
+func (l *zerologLogger) Fatal(cat Category, sub SubCategory, msg string, extra map[ExtraKey]interface{}) {
+	l.log(l.logger.Fatal(), msg, prepareLogInfo(extra, cat, sub))
+}
+func (l *zerologLogger) Fatalf(template string, args ...interface{}) {
+	l.log(l.logger.Fatal(), fmt.Sprintf(template, args...), nil)
+}
+
+func (l *zerologLogger) WithContext(ctx context.Context) Logger {
+	fields := contextFields(ctx)
+	if len(fields) == 0 {
+		return l
+	}
+	return &zerologLogger{
+		cfg:    l.cfg,
+		logger: l.logger.With().Fields(fields).Logger(),
+	}
+}
+
+// Sync has nothing to flush: zerolog writes every event through.
+func (l *zerologLogger) Sync() error { return nil }
@@ -0,0 +1,100 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"net/http"
+)
+
+const (
+	Success = "success"
+	Failure = "failure"
+)
+
+// Registry holds every metric of the API. It is separate from the prometheus default registry, so /metrics exposes
+// exactly what is declared here.
+var Registry = prometheus.NewRegistry()
+
+var factory = promauto.With(Registry)
+
+var (
+	HttpRequests = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "HTTP requests by route template, status and result code.",
+	}, []string{"method", "route", "status", "result_code"})
+
+	HttpRequestDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "HTTP request latency by route template.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	DbQueryDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "db_query_duration_seconds",
+		Help:    "Gorm statement latency by operation and table.",
+		Buckets: []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"operation", "table", "result"})
+
+	RedisCommandDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "redis_command_duration_seconds",
+		Help:    "Redis command latency by command.",
+		Buckets: []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5},
+	}, []string{"command", "result"})
+
+	OtpSent = factory.NewCounter(prometheus.CounterOpts{
+		Name: "otp_sent_total",
+		Help: "One time passwords sent.",
+	})
+
+	OtpValidations = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "otp_validations_total",
+		Help: "One time password validations by outcome: valid, invalid, used, expired or error.",
+	}, []string{"outcome"})
+
+	Logins = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "logins_total",
+		Help: "Logins by method and outcome.",
+	}, []string{"method", "outcome"})
+
+	RateLimitRejections = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "rate_limit_rejections_total",
+		Help: "Requests rejected by a rate limit policy.",
+	}, []string{"policy"})
+
+	LogEntriesDropped = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "log_entries_dropped_total",
+		Help: "Log entries dropped by reason: sampled or duplicate.",
+	}, []string{"reason"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		collectors.NewExpvarCollector(map[string]*prometheus.Desc{
+			"ip_rate_limiter_entries": prometheus.NewDesc(
+				"ip_rate_limiter_entries",
+				"Client IPs tracked by the in-process rate limiters.",
+				[]string{"limiter"}, nil,
+			),
+		}),
+	)
+}
+
+// RecordLogin counts a login attempt with the given method, failed if err is not nil.
+func RecordLogin(method string, err error) {
+	Logins.WithLabelValues(method, Outcome(err)).Inc()
+}
+
+func Outcome(err error) string {
+	if err != nil {
+		return Failure
+	}
+	return Success
+}
+
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
@@ -0,0 +1,101 @@
+package oauth
+
+import (
+	"base_structure/src/config"
+	"context"
+	"encoding/json"
+	"fmt"
+	"golang.org/x/oauth2"
+	"net/http"
+	"strings"
+)
+
+const (
+	defaultSubjectField = "sub"
+	defaultEmailField   = "email"
+)
+
+// oauth2Provider covers providers without OIDC discovery (e.g. GitHub) by reading the profile from a user info
+// endpoint.
+type oauth2Provider struct {
+	name string
+	cfg  config.OAuthProviderConfig
+	conf *oauth2.Config
+}
+
+func newOAuth2Provider(name string, cfg config.OAuthProviderConfig) *oauth2Provider {
+	return &oauth2Provider{
+		name: name,
+		cfg:  cfg,
+		conf: &oauth2.Config{
+			ClientID:     cfg.ClientId,
+			ClientSecret: cfg.ClientSecret,
+			RedirectURL:  cfg.RedirectUrl,
+			Endpoint:     oauth2.Endpoint{AuthURL: cfg.AuthUrl, TokenURL: cfg.TokenUrl},
+			Scopes:       cfg.Scopes,
+		},
+	}
+}
+
+func (p *oauth2Provider) Name() string {
+	return p.name
+}
+
+func (p *oauth2Provider) AuthCodeURL(_ context.Context, state string, _ string, verifier string) (string, error) {
+	return p.conf.AuthCodeURL(state, oauth2.S256ChallengeOption(verifier)), nil
+}
+
+func (p *oauth2Provider) Exchange(ctx context.Context, code string, _ string, verifier string) (*Identity, error) {
+	token, err := p.conf.Exchange(ctx, code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.cfg.UserInfoUrl, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	res, err := p.conf.Client(ctx, token).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = res.Body.Close() }()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("user info request failed: %s", res.Status)
+	}
+	var info map[string]interface{}
+	decoder := json.NewDecoder(res.Body)
+	decoder.UseNumber()
+	if err = decoder.Decode(&info); err != nil {
+		return nil, err
+	}
+	identity := &Identity{
+		Subject: claimString(info, p.field(p.cfg.SubjectField, defaultSubjectField)),
+		Email:   claimString(info, p.field(p.cfg.EmailField, defaultEmailField)),
+	}
+	if identity.Subject == "" {
+		return nil, ErrMissingSubject
+	}
+	if name := claimString(info, "name"); name != "" {
+		identity.FirstName, identity.LastName, _ = strings.Cut(name, " ")
+	}
+	return identity, nil
+}
+
+func (p *oauth2Provider) field(configured string, fallback string) string {
+	if configured != "" {
+		return configured
+	}
+	return fallback
+}
+
+func claimString(info map[string]interface{}, key string) string {
+	switch v := info[key].(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,101 @@
+package oauth
+
+import (
+	"base_structure/src/config"
+	"context"
+	"github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+	"sync"
+)
+
+var defaultOidcScopes = []string{oidc.ScopeOpenID, "email", "profile"}
+
+type oidcProvider struct {
+	name     string
+	cfg      config.OAuthProviderConfig
+	mu       sync.Mutex
+	provider *oidc.Provider
+}
+
+func newOidcProvider(name string, cfg config.OAuthProviderConfig) *oidcProvider {
+	return &oidcProvider{name: name, cfg: cfg}
+}
+
+func (p *oidcProvider) Name() string {
+	return p.name
+}
+
+// discover fetches the issuer metadata on first use, so an unreachable provider doesn't block start-up.
+func (p *oidcProvider) discover(ctx context.Context) (*oidc.Provider, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.provider != nil {
+		return p.provider, nil
+	}
+	provider, err := oidc.NewProvider(ctx, p.cfg.Issuer)
+	if err != nil {
+		return nil, err
+	}
+	p.provider = provider
+	return provider, nil
+}
+
+func (p *oidcProvider) oauth2Config(provider *oidc.Provider) *oauth2.Config {
+	scopes := p.cfg.Scopes
+	if len(scopes) == 0 {
+		scopes = defaultOidcScopes
+	}
+	return &oauth2.Config{
+		ClientID:     p.cfg.ClientId,
+		ClientSecret: p.cfg.ClientSecret,
+		RedirectURL:  p.cfg.RedirectUrl,
+		Endpoint:     provider.Endpoint(),
+		Scopes:       scopes,
+	}
+}
+
+func (p *oidcProvider) AuthCodeURL(ctx context.Context, state string, nonce string, verifier string) (string, error) {
+	provider, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+	return p.oauth2Config(provider).AuthCodeURL(state, oidc.Nonce(nonce), oauth2.S256ChallengeOption(verifier)), nil
+}
+
+func (p *oidcProvider) Exchange(ctx context.Context, code string, nonce string, verifier string) (*Identity, error) {
+	provider, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+	token, err := p.oauth2Config(provider).Exchange(ctx, code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return nil, err
+	}
+	rawIdToken, ok := token.Extra("id_token").(string)
+	if !ok || rawIdToken == "" {
+		return nil, ErrMissingIdToken
+	}
+	idToken, err := provider.Verifier(&oidc.Config{ClientID: p.cfg.ClientId}).Verify(ctx, rawIdToken)
+	if err != nil {
+		return nil, err
+	}
+	if idToken.Nonce != nonce {
+		return nil, ErrNonceMismatch
+	}
+	var claims struct {
+		Email         string `json:"email"`
+		EmailVerified bool   `json:"email_verified"`
+		GivenName     string `json:"given_name"`
+		FamilyName    string `json:"family_name"`
+	}
+	if err = idToken.Claims(&claims); err != nil {
+		return nil, err
+	}
+	return &Identity{
+		Subject:       idToken.Subject,
+		Email:         claims.Email,
+		EmailVerified: claims.EmailVerified,
+		FirstName:     claims.GivenName,
+		LastName:      claims.FamilyName,
+	}, nil
+}
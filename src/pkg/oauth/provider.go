@@ -0,0 +1,45 @@
+package oauth
+
+import (
+	"base_structure/src/config"
+	"context"
+	"errors"
+)
+
+var (
+	ErrNonceMismatch  = errors.New("id token nonce mismatch")
+	ErrMissingIdToken = errors.New("id token missing from token response")
+	ErrMissingSubject = errors.New("subject missing from user info")
+)
+
+// Identity is the normalized profile returned by an external identity provider.
+type Identity struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	FirstName     string
+	LastName      string
+}
+
+// Provider drives the authorization code flow (with PKCE) against one external identity provider.
+type Provider interface {
+	Name() string
+	AuthCodeURL(ctx context.Context, state string, nonce string, verifier string) (string, error)
+	Exchange(ctx context.Context, code string, nonce string, verifier string) (*Identity, error)
+}
+
+// NewProvider returns an OIDC provider when an issuer is configured, and a plain OAuth2 provider otherwise.
+func NewProvider(name string, cfg config.OAuthProviderConfig) Provider {
+	if cfg.Issuer != "" {
+		return newOidcProvider(name, cfg)
+	}
+	return newOAuth2Provider(name, cfg)
+}
+
+func NewProviders(cfg *config.Config) map[string]Provider {
+	providers := make(map[string]Provider, len(cfg.OAuth.Providers))
+	for name, pc := range cfg.OAuth.Providers {
+		providers[name] = NewProvider(name, pc)
+	}
+	return providers
+}
@@ -0,0 +1,50 @@
+package requestid
+
+import (
+	"context"
+	"github.com/google/uuid"
+)
+
+// Header carries the request id in both directions.
+const Header = "X-Request-ID"
+
+// maxLength matches the audit_logs.request_id column.
+const maxLength = 64
+
+type key struct{}
+
+// New returns a random request id.
+func New() string {
+	return uuid.NewString()
+}
+
+// Valid reports whether an id received from a client can be reused: 1 to 64 letters, digits or any of "-_.:". Other
+// ids are replaced, so they never reach the logs or the audit trail.
+func Valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '_', r == '.', r == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// WithId returns a context carrying the request id.
+func WithId(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, key{}, id)
+}
+
+// FromContext returns the request id carried by ctx, or "" outside a request.
+func FromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(key{}).(string)
+	return id
+}
@@ -0,0 +1,127 @@
+package service_errors
+
+import (
+	"errors"
+	"github.com/go-redis/redis/v7"
+	"gorm.io/gorm"
+	"net/http"
+)
+
+// Kind is an entry of the error catalogue. Code is the resultCode of the response, its first three digits being the
+// HTTP Status. Message is the default end user message and Key its translation key.
+type Kind struct {
+	Code    int
+	Status  int
+	Message string
+	Key     string
+}
+
+func (k *Kind) Error() string {
+	return k.Message
+}
+
+var (
+	catalogue []*Kind
+	byCode    = map[int]*Kind{}
+)
+
+func register(code int, status int, message string, key string) *Kind {
+	k := &Kind{Code: code, Status: status, Message: message, Key: key}
+	catalogue = append(catalogue, k)
+	byCode[code] = k
+	return k
+}
+
+var (
+	// Otp
+	ErrOtpExists   = register(40902, http.StatusConflict, OtpExists, "error.otp_exists")
+	ErrOtpUsed     = register(40903, http.StatusConflict, OtpUsed, "error.otp_used")
+	ErrOtpNotValid = register(40002, http.StatusBadRequest, OtpNotValid, "error.otp_not_valid")
+	ErrOtpExpired  = register(40005, http.StatusBadRequest, OtpExpired, "error.otp_expired")
+
+	// Token
+	ErrUnexpected     = register(50003, http.StatusInternalServerError, UnexpectedError, "error.unexpected")
+	ErrClaimsNotFound = register(40106, http.StatusUnauthorized, ClaimsNotFound, "error.claims_not_found")
+	ErrTokenRequired  = register(40103, http.StatusUnauthorized, TokenRequired, "error.token_required")
+	ErrTokenExpired   = register(40104, http.StatusUnauthorized, TokenExpired, "error.token_expired")
+	ErrTokenInvalid   = register(40105, http.StatusUnauthorized, TokenInvalid, "error.token_invalid")
+
+	// User
+	ErrEmailExists        = register(40904, http.StatusConflict, EmailExists, "error.email_exists")
+	ErrUsernameExists     = register(40905, http.StatusConflict, UsernameExists, "error.username_exists")
+	ErrPermissionDenied   = register(40301, http.StatusForbidden, PermissionDenied, "error.permission_denied")
+	ErrInvalidCredentials = register(40102, http.StatusUnauthorized, InvalidCredentials, "error.invalid_credentials")
+
+	// WebAuthn
+	ErrWebAuthnChallengeNotFound  = register(40003, http.StatusBadRequest, WebAuthnChallengeNotFound, "error.webauthn_challenge_not_found")
+	ErrWebAuthnVerificationFailed = register(40107, http.StatusUnauthorized, WebAuthnVerificationFailed, "error.webauthn_verification_failed")
+	ErrWebAuthnNoCredentials      = register(40108, http.StatusUnauthorized, WebAuthnNoCredentials, "error.webauthn_no_credentials")
+
+	// OAuth
+	ErrOAuthProviderNotFound     = register(40402, http.StatusNotFound, OAuthProviderNotFound, "error.oauth_provider_not_found")
+	ErrOAuthStateInvalid         = register(40004, http.StatusBadRequest, OAuthStateInvalid, "error.oauth_state_invalid")
+	ErrOAuthExchangeFailed       = register(40109, http.StatusUnauthorized, OAuthExchangeFailed, "error.oauth_exchange_failed")
+	ErrExternalIdentityLinked    = register(40906, http.StatusConflict, ExternalIdentityLinked, "error.external_identity_linked")
+	ErrExternalIdentityNotFound  = register(40403, http.StatusNotFound, ExternalIdentityNotFound, "error.external_identity_not_found")
+	ErrExternalIdentityLastLogin = register(40907, http.StatusConflict, ExternalIdentityLastLogin, "error.external_identity_last_login")
+
+	// Request
+	ErrTooManyRequests    = register(42901, http.StatusTooManyRequests, TooManyRequests, "error.too_many_requests")
+	ErrOtpTooManyRequests = register(42902, http.StatusTooManyRequests, OtpTooManyRequests, "error.otp_too_many_requests")
+
+	// DB
+	ErrRecordNotFound = register(40401, http.StatusNotFound, RecordNotFound, "error.record_not_found")
+
+	// ErrInternal is the kind of every error outside the catalogue. Its message hides the cause from the client.
+	ErrInternal = register(50002, http.StatusInternalServerError, InternalError, "error.internal")
+)
+
+// Catalogue returns every kind, in declaration order.
+func Catalogue() []*Kind {
+	return append([]*Kind(nil), catalogue...)
+}
+
+// KindByCode returns the kind with the given result code, if any.
+func KindByCode(code int) (*Kind, bool) {
+	k, ok := byCode[code]
+	return k, ok
+}
+
+// New returns an error of kind with its default message.
+func New(kind *Kind) *ServiceError {
+	return &ServiceError{EndUserMessage: kind.Message, Kind: kind}
+}
+
+// Wrap returns an error of kind caused by err, which stays reachable through errors.Is and errors.As.
+func Wrap(kind *Kind, err error) *ServiceError {
+	return &ServiceError{EndUserMessage: kind.Message, Kind: kind, Err: err}
+}
+
+// KindOf returns the catalogue kind of err. gorm.ErrRecordNotFound and redis.Nil are record not found; any other error
+// outside the catalogue is ErrInternal.
+func KindOf(err error) *Kind {
+	if err == nil {
+		return nil
+	}
+	var se *ServiceError
+	if errors.As(err, &se) && se.Kind != nil {
+		return se.Kind
+	}
+	var k *Kind
+	if errors.As(err, &k) {
+		return k
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) || errors.Is(err, redis.Nil) {
+		return ErrRecordNotFound
+	}
+	return ErrInternal
+}
+
+// From returns err as a ServiceError of its catalogue kind, wrapping it if needed.
+func From(err error) *ServiceError {
+	var se *ServiceError
+	if errors.As(err, &se) && se.Kind != nil {
+		return se
+	}
+	return Wrap(KindOf(err), err)
+}
@@ -7,6 +7,8 @@ const (
 	OtpUsed = "otp used"
 	// OtpNotValid => Otp
 	OtpNotValid = "otp not valid"
+	// OtpExpired => Otp
+	OtpExpired = "otp expired"
 
 	// UnexpectedError => Token
 	UnexpectedError = "unexpected error"
@@ -28,6 +30,34 @@ const (
 	// InvalidCredentials => User
 	InvalidCredentials = "invalid credentials"
 
+	// WebAuthnChallengeNotFound => WebAuthn
+	WebAuthnChallengeNotFound = "webauthn challenge not found"
+	// WebAuthnVerificationFailed => WebAuthn
+	WebAuthnVerificationFailed = "webauthn verification failed"
+	// WebAuthnNoCredentials => WebAuthn
+	WebAuthnNoCredentials = "no webauthn credentials registered"
+
+	// OAuthProviderNotFound => OAuth
+	OAuthProviderNotFound = "oauth provider not found"
+	// OAuthStateInvalid => OAuth
+	OAuthStateInvalid = "oauth state invalid"
+	// OAuthExchangeFailed => OAuth
+	OAuthExchangeFailed = "oauth exchange failed"
+	// ExternalIdentityLinked => OAuth
+	ExternalIdentityLinked = "external identity already linked"
+	// ExternalIdentityNotFound => OAuth
+	ExternalIdentityNotFound = "external identity not found"
+	// ExternalIdentityLastLogin => OAuth
+	ExternalIdentityLastLogin = "cannot unlink the last login method"
+
+	// TooManyRequests => Request
+	TooManyRequests = "too many requests"
+	// OtpTooManyRequests => Request
+	OtpTooManyRequests = "not allowed"
+
 	// RecordNotFound => DB
 	RecordNotFound = "record not found"
+
+	// InternalError => Internal
+	InternalError = "internal error"
 )
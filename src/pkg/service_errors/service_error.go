@@ -1,11 +1,35 @@
 package service_errors
 
+import "base_structure/src/pkg/i18n"
+
+// ServiceError is an error of the catalogue returned by the services. Kind gives its code, status and translation key.
 type ServiceError struct {
 	EndUserMessage   string `json:"endUserMessage"`
 	TechnicalMessage string `json:"technicalMessage"`
 	Err              error
+	Kind             *Kind `json:"-"`
 }
 
 func (e *ServiceError) Error() string {
 	return e.EndUserMessage
 }
+
+func (e *ServiceError) Unwrap() error {
+	return e.Err
+}
+
+// Is makes errors.Is(err, service_errors.ErrOtpUsed) match an error of that kind.
+func (e *ServiceError) Is(target error) bool {
+	k, ok := target.(*Kind)
+	return ok && k == e.Kind
+}
+
+// Localize returns a copy of e whose EndUserMessage is in lang. Errors outside the catalogue are returned as is.
+func (e *ServiceError) Localize(lang string) *ServiceError {
+	if e.Kind == nil {
+		return e
+	}
+	localized := *e
+	localized.EndUserMessage = i18n.T(lang, e.Kind.Key, nil)
+	return &localized
+}
@@ -0,0 +1,82 @@
+package service_errors
+
+import (
+	"base_structure/src/pkg/i18n"
+	"errors"
+	"fmt"
+	"github.com/go-redis/redis/v7"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+	"net/http"
+	"testing"
+)
+
+func TestCatalogueCodesAndKeysAreUnique(t *testing.T) {
+	codes := map[int]bool{}
+	keys := map[string]bool{}
+	for _, k := range Catalogue() {
+		assert.False(t, codes[k.Code], "duplicate code %d", k.Code)
+		assert.False(t, keys[k.Key], "duplicate key %s", k.Key)
+		assert.Equal(t, k.Status, k.Code/100, "code %d does not start with its status", k.Code)
+		codes[k.Code] = true
+		keys[k.Key] = true
+	}
+}
+
+func TestErrorsIsMatchesKindThroughWrapping(t *testing.T) {
+	err := fmt.Errorf("validate: %w", New(ErrOtpUsed))
+
+	assert.True(t, errors.Is(err, ErrOtpUsed))
+	assert.False(t, errors.Is(err, ErrOtpNotValid))
+	assert.Equal(t, ErrOtpUsed, KindOf(err))
+}
+
+func TestWrapKeepsCause(t *testing.T) {
+	err := Wrap(ErrOtpExpired, redis.Nil)
+
+	assert.True(t, errors.Is(err, redis.Nil))
+	assert.Equal(t, ErrOtpExpired, KindOf(err))
+}
+
+func TestKindOfServiceErrorWithoutKind(t *testing.T) {
+	err := &ServiceError{EndUserMessage: EmailExists}
+
+	assert.False(t, errors.Is(err, ErrEmailExists))
+	assert.Equal(t, http.StatusInternalServerError, KindOf(err).Status)
+}
+
+func TestKindOfErrorsOutsideCatalogue(t *testing.T) {
+	assert.Nil(t, KindOf(nil))
+	assert.Equal(t, ErrRecordNotFound, KindOf(gorm.ErrRecordNotFound))
+	assert.Equal(t, ErrRecordNotFound, KindOf(fmt.Errorf("get: %w", redis.Nil)))
+	assert.Equal(t, ErrInternal, KindOf(errors.New("connection refused")))
+}
+
+func TestFrom(t *testing.T) {
+	se := New(ErrTokenExpired)
+	assert.Same(t, se, From(se))
+
+	cause := errors.New("connection refused")
+	internal := From(cause)
+	assert.Equal(t, ErrInternal, internal.Kind)
+	assert.Equal(t, InternalError, internal.Error())
+	assert.ErrorIs(t, internal, cause)
+}
+
+func TestCatalogueIsTranslated(t *testing.T) {
+	for _, k := range Catalogue() {
+		assert.Equal(t, k.Message, i18n.T(i18n.En, k.Key, nil), k.Key)
+		_, ok := i18n.Lookup(i18n.Fa, k.Key)
+		assert.True(t, ok, k.Key)
+	}
+}
+
+func TestLocalize(t *testing.T) {
+	err := Wrap(ErrOtpUsed, redis.Nil)
+	fa := err.Localize(i18n.Fa)
+
+	assert.Equal(t, "کد یکبار مصرف قبلاً استفاده شده است", fa.EndUserMessage)
+	assert.Equal(t, OtpUsed, err.EndUserMessage)
+	assert.ErrorIs(t, fa, ErrOtpUsed)
+	assert.ErrorIs(t, fa, redis.Nil)
+}
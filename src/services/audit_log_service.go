@@ -0,0 +1,88 @@
+package services
+
+import (
+	"base_structure/src/api/dto"
+	"base_structure/src/config"
+	"base_structure/src/data/db"
+	"base_structure/src/data/models"
+	"base_structure/src/data/repository"
+	"base_structure/src/pkg/logging"
+	"context"
+	"encoding/json"
+)
+
+type AuditLogService struct {
+	logger logging.Logger
+	logs   repository.Repository[models.AuditLog]
+}
+
+func NewAuditLogService(cfg *config.Config) *AuditLogService {
+	return &AuditLogService{
+		logger: logging.NewLogger(cfg),
+		logs:   repository.NewRepository[models.AuditLog](db.GetDb(cfg)),
+	}
+}
+
+// List returns the matching records, newest first.
+func (s *AuditLogService) List(ctx context.Context, req *dto.AuditLogFilter) (*dto.PagedList[dto.AuditLogResponse], error) {
+	var filters []repository.QueryOption
+	if req.ActorId != nil {
+		filters = append(filters, repository.Filter("actor_id", *req.ActorId))
+	}
+	if req.Action != "" {
+		filters = append(filters, repository.Filter("action", req.Action))
+	}
+	if req.TargetType != "" {
+		filters = append(filters, repository.Filter("target_type", req.TargetType))
+	}
+	if req.TargetId != "" {
+		filters = append(filters, repository.Filter("target_id", req.TargetId))
+	}
+	if !req.From.IsZero() {
+		filters = append(filters, repository.Where("created_at >= ?", req.From))
+	}
+	if !req.To.IsZero() {
+		filters = append(filters, repository.Where("created_at < ?", req.To))
+	}
+	total, err := s.logs.Count(ctx, filters...)
+	if err != nil {
+		s.logger.WithContext(ctx).Error(logging.Postgres, logging.Select, err.Error(), nil)
+		return nil, err
+	}
+	page := repository.NewPage(req.PageNumber, req.PageSize)
+	logs, err := s.logs.List(ctx, append(filters, repository.Sort("id", true), page.Option())...)
+	if err != nil {
+		s.logger.WithContext(ctx).Error(logging.Postgres, logging.Select, err.Error(), nil)
+		return nil, err
+	}
+	items := make([]dto.AuditLogResponse, 0, len(logs))
+	for _, l := range logs {
+		items = append(items, dto.AuditLogResponse{
+			Id:         l.ID,
+			CreatedAt:  l.CreatedAt,
+			ActorId:    l.ActorId,
+			Action:     l.Action,
+			TargetType: l.TargetType,
+			TargetId:   l.TargetId,
+			Ip:         l.Ip,
+			UserAgent:  l.UserAgent,
+			RequestId:  l.RequestId,
+			Before:     rawJson(l.Before),
+			After:      rawJson(l.After),
+		})
+	}
+	return &dto.PagedList[dto.AuditLogResponse]{
+		PageNumber: page.Number,
+		PageSize:   page.Size,
+		TotalRows:  total,
+		TotalPages: page.TotalPages(total),
+		Items:      items,
+	}, nil
+}
+
+func rawJson(s string) json.RawMessage {
+	if s == "" {
+		return nil
+	}
+	return json.RawMessage(s)
+}
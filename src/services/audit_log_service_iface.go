@@ -0,0 +1,10 @@
+package services
+
+import (
+	"base_structure/src/api/dto"
+	"context"
+)
+
+type AuditLogServiceIface interface {
+	List(ctx context.Context, req *dto.AuditLogFilter) (*dto.PagedList[dto.AuditLogResponse], error)
+}
@@ -0,0 +1,195 @@
+package services
+
+import (
+	"base_structure/src/config"
+	"base_structure/src/data/db"
+	"base_structure/src/data/models"
+	"base_structure/src/pkg/actor"
+	"base_structure/src/pkg/audit"
+	"base_structure/src/pkg/logging"
+	"context"
+	"encoding/json"
+	"gorm.io/gorm"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	defaultAuditBufferSize    = 1024
+	defaultAuditBatchSize     = 100
+	defaultAuditFlushInterval = 2 * time.Second
+)
+
+type AuditEvent struct {
+	Action audit.Action
+	// UserId is the actor when the request isn't authenticated, e.g. the user who is logging in.
+	UserId     uint
+	TargetType string
+	TargetId   string
+	// Before and After are reduced to the fields that changed; either may be nil.
+	Before interface{}
+	After  interface{}
+}
+
+type AuditLoggerIface interface {
+	Log(ctx context.Context, event AuditEvent)
+}
+
+// AuditLogger writes audit records to Postgres in the background, in batches. Log never blocks on the database
+// unless the buffer is full, in which case the record is written synchronously instead of being dropped.
+type AuditLogger struct {
+	logger        logging.Logger
+	database      *gorm.DB
+	queue         chan models.AuditLog
+	batchSize     int
+	flushInterval time.Duration
+	mu            sync.RWMutex
+	closed        bool
+	done          chan struct{}
+}
+
+var (
+	auditLogger     *AuditLogger
+	auditLoggerInit sync.Once
+)
+
+// GetAuditLogger returns the process-wide audit logger.
+func GetAuditLogger(cfg *config.Config) *AuditLogger {
+	auditLoggerInit.Do(func() {
+		auditLogger = NewAuditLogger(cfg, db.GetDb(cfg))
+	})
+	return auditLogger
+}
+
+func NewAuditLogger(cfg *config.Config, database *gorm.DB) *AuditLogger {
+	a := &AuditLogger{
+		logger:        logging.NewLogger(cfg),
+		database:      database,
+		queue:         make(chan models.AuditLog, orDefault(cfg.Audit.BufferSize, defaultAuditBufferSize)),
+		batchSize:     orDefault(cfg.Audit.BatchSize, defaultAuditBatchSize),
+		flushInterval: cfg.Audit.FlushInterval * time.Second,
+		done:          make(chan struct{}),
+	}
+	if a.flushInterval <= 0 {
+		a.flushInterval = defaultAuditFlushInterval
+	}
+	go a.run()
+	return a
+}
+
+func (a *AuditLogger) Log(ctx context.Context, event AuditEvent) {
+	record := a.record(ctx, event)
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if !a.closed {
+		select {
+		case a.queue <- record:
+			return
+		default:
+		}
+	}
+	a.write([]models.AuditLog{record})
+}
+
+// Close flushes the buffered records and stops the background writer.
+func (a *AuditLogger) Close() {
+	a.mu.Lock()
+	if a.closed {
+		a.mu.Unlock()
+		return
+	}
+	a.closed = true
+	close(a.queue)
+	a.mu.Unlock()
+	<-a.done
+}
+
+func (a *AuditLogger) run() {
+	ticker := time.NewTicker(a.flushInterval)
+	defer ticker.Stop()
+	batch := make([]models.AuditLog, 0, a.batchSize)
+	for {
+		select {
+		case record, ok := <-a.queue:
+			if !ok {
+				a.write(batch)
+				close(a.done)
+				return
+			}
+			batch = append(batch, record)
+			if len(batch) >= a.batchSize {
+				a.write(batch)
+				batch = batch[:0]
+			}
+		case <-ticker.C:
+			a.write(batch)
+			batch = batch[:0]
+		}
+	}
+}
+
+func (a *AuditLogger) write(records []models.AuditLog) {
+	if len(records) == 0 {
+		return
+	}
+	err := a.database.CreateInBatches(records, a.batchSize).Error
+	if err != nil {
+		a.logger.Error(logging.Postgres, logging.Audit, "cannot write audit records: "+err.Error(), nil)
+	}
+}
+
+func (a *AuditLogger) record(ctx context.Context, event AuditEvent) models.AuditLog {
+	info := audit.RequestInfoFromContext(ctx)
+	actorId, ok := actor.FromContext(ctx)
+	if !ok && event.UserId != 0 {
+		actorId = int(event.UserId)
+	}
+	record := models.AuditLog{
+		CreatedAt:  time.Now(),
+		ActorId:    actorId,
+		Action:     string(event.Action),
+		TargetType: event.TargetType,
+		TargetId:   event.TargetId,
+		Ip:         info.Ip,
+		UserAgent:  truncateRunes(info.UserAgent, 255),
+		RequestId:  info.RequestId,
+	}
+	before, after, err := audit.Diff(event.Before, event.After)
+	if err != nil {
+		a.logger.WithContext(ctx).Warn(logging.Internal, logging.Audit, "cannot diff audit values: "+err.Error(), nil)
+		return record
+	}
+	record.Before = toJson(before)
+	record.After = toJson(after)
+	return record
+}
+
+// auditLogin records a login of userId, or a failed one when err is set. userId is zero when the login failed before
+// the user was known.
+func auditLogin(ctx context.Context, a AuditLoggerIface, userId uint, err error) {
+	event := AuditEvent{Action: audit.Login, UserId: userId}
+	if err != nil {
+		event.Action = audit.LoginFailed
+	}
+	if userId != 0 {
+		event.TargetType = audit.TargetUser
+		event.TargetId = strconv.FormatUint(uint64(userId), 10)
+	}
+	a.Log(ctx, event)
+}
+
+func toJson(m map[string]interface{}) string {
+	if m == nil {
+		return ""
+	}
+	b, _ := json.Marshal(m)
+	return string(b)
+}
+
+func orDefault(v, def int) int {
+	if v <= 0 {
+		return def
+	}
+	return v
+}
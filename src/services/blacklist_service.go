@@ -3,6 +3,7 @@ package services
 import (
 	"base_structure/src/config"
 	"base_structure/src/data/cache"
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
 	"github.com/go-redis/redis/v7"
@@ -17,12 +18,12 @@ func NewBlacklistService(cfg *config.Config) *BlacklistService {
 	return &BlacklistService{redis: cache.GetRedis(cfg)}
 }
 
-func (b *BlacklistService) Blacklist(token string, ttl time.Duration) error {
-	return b.redis.Set(hashToken(token), 1, ttl).Err()
+func (b *BlacklistService) Blacklist(ctx context.Context, token string, ttl time.Duration) error {
+	return b.redis.WithContext(ctx).Set(hashToken(token), 1, ttl).Err()
 }
 
-func (b *BlacklistService) IsBlacklisted(token string) (bool, error) {
-	n, err := b.redis.Exists(hashToken(token)).Result()
+func (b *BlacklistService) IsBlacklisted(ctx context.Context, token string) (bool, error) {
+	n, err := b.redis.WithContext(ctx).Exists(hashToken(token)).Result()
 	return n == 1, err
 }
 
@@ -0,0 +1,401 @@
+package services
+
+import (
+	"base_structure/src/api/dto"
+	"base_structure/src/common"
+	"base_structure/src/config"
+	"base_structure/src/constants"
+	"base_structure/src/data/cache"
+	"base_structure/src/data/db"
+	"base_structure/src/data/models"
+	"base_structure/src/data/repository"
+	"base_structure/src/pkg/audit"
+	"base_structure/src/pkg/logging"
+	"base_structure/src/pkg/metrics"
+	"base_structure/src/pkg/oauth"
+	"base_structure/src/pkg/service_errors"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/go-redis/redis/v7"
+	"golang.org/x/oauth2"
+	"gorm.io/gorm"
+	"time"
+)
+
+const (
+	oauthRequestTimeout = 10 * time.Second
+	usernameAttempts    = 5
+)
+
+type ExternalAuthService struct {
+	logger       logging.Logger
+	cfg          *config.Config
+	redisClient  *redis.Client
+	database     *gorm.DB
+	tokenService *TokenService
+	userService  *UserService
+	audit        AuditLoggerIface
+	uow          db.UnitOfWork
+	users        repository.UserRepository
+	identities   repository.Repository[models.ExternalIdentity]
+	providers    map[string]oauth.Provider
+}
+
+type oauthState struct {
+	Provider   string
+	Nonce      string
+	Verifier   string
+	LinkUserId uint
+}
+
+func NewExternalAuthService(cfg *config.Config) *ExternalAuthService {
+	database := db.GetDb(cfg)
+	return &ExternalAuthService{
+		logger:       logging.NewLogger(cfg),
+		cfg:          cfg,
+		redisClient:  cache.GetRedis(cfg),
+		database:     database,
+		tokenService: NewTokenService(cfg),
+		userService:  NewUserService(cfg),
+		audit:        GetAuditLogger(cfg),
+		uow:          db.NewUnitOfWork(database),
+		users:        repository.NewUserRepository(database),
+		identities:   repository.NewRepository[models.ExternalIdentity](database),
+		providers:    oauth.NewProviders(cfg),
+	}
+}
+
+// AuthUrl starts an authorization code flow. A non-zero linkUserId binds the flow to that user, so only LinkCallback
+// called by that user can complete it.
+func (s *ExternalAuthService) AuthUrl(ctx context.Context, provider string, linkUserId uint) (*dto.OAuthAuthUrlResponse, error) {
+	p, ok := s.providers[provider]
+	if !ok {
+		return nil, service_errors.New(service_errors.ErrOAuthProviderNotFound)
+	}
+	state, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+	st := &oauthState{Provider: provider, Nonce: nonce, Verifier: oauth2.GenerateVerifier(), LinkUserId: linkUserId}
+	err = cache.Set(ctx, s.redisClient, s.stateKey(state), st, s.cfg.OAuth.StateExpireTime*time.Second)
+	if err != nil {
+		s.logger.WithContext(ctx).Error(logging.Redis, logging.OAuth, err.Error(), nil)
+		return nil, err
+	}
+	discoveryCtx, cancel := context.WithTimeout(ctx, oauthRequestTimeout)
+	defer cancel()
+	url, err := p.AuthCodeURL(discoveryCtx, state, st.Nonce, st.Verifier)
+	if err != nil {
+		s.logger.WithContext(ctx).Error(logging.General, logging.OAuth, err.Error(), nil)
+		return nil, err
+	}
+	return &dto.OAuthAuthUrlResponse{AuthUrl: url}, nil
+}
+
+// Callback finishes a login flow: it logs in the owner of the external identity, provisioning a user the first time
+// the identity is seen. States started by AuthUrl for linking are rejected, as they only complete through LinkCallback.
+func (s *ExternalAuthService) Callback(ctx context.Context, provider string, req *dto.OAuthCallbackRequest) (_ *dto.TokenDetail, err error) {
+	var userId uint
+	defer func() {
+		metrics.RecordLogin("oauth", err)
+		if userId == 0 && err != nil {
+			s.audit.Log(ctx, AuditEvent{Action: audit.LoginFailed, TargetType: audit.TargetExternalIdentity, TargetId: provider})
+			return
+		}
+		auditLogin(ctx, s.audit, userId, err)
+	}()
+	identity, err := s.redeem(ctx, provider, 0, req)
+	if err != nil {
+		return nil, err
+	}
+	ei, found, err := s.findIdentity(ctx, provider, identity.Subject)
+	if err != nil {
+		return nil, err
+	}
+	if found {
+		userId = ei.UserId
+	} else {
+		userId, err = s.provision(ctx, provider, identity)
+		if err != nil {
+			return nil, err
+		}
+	}
+	user, err := s.users.FindByID(ctx, userId, repository.WithRoles())
+	if err != nil {
+		return nil, err
+	}
+	return s.tokenService.GenerateToken(newTokenDto(user))
+}
+
+// LinkCallback finishes a link flow for the authenticated userId, who must be the user that started it.
+func (s *ExternalAuthService) LinkCallback(ctx context.Context, userId uint, provider string, req *dto.OAuthCallbackRequest) error {
+	identity, err := s.redeem(ctx, provider, userId, req)
+	if err != nil {
+		return err
+	}
+	ei, found, err := s.findIdentity(ctx, provider, identity.Subject)
+	if err != nil {
+		return err
+	}
+	if found {
+		if ei.UserId != userId {
+			return service_errors.New(service_errors.ErrExternalIdentityLinked)
+		}
+		return nil
+	}
+	return s.link(ctx, userId, provider, identity)
+}
+
+// redeem consumes the state of a flow started for linkUserId, zero for a login, and exchanges the code for the
+// external identity.
+func (s *ExternalAuthService) redeem(ctx context.Context, provider string, linkUserId uint, req *dto.OAuthCallbackRequest) (*oauth.Identity, error) {
+	p, ok := s.providers[provider]
+	if !ok {
+		return nil, service_errors.New(service_errors.ErrOAuthProviderNotFound)
+	}
+	st, err := s.popState(ctx, req.State)
+	if err != nil {
+		return nil, err
+	}
+	if st.Provider != provider || st.LinkUserId != linkUserId {
+		return nil, service_errors.New(service_errors.ErrOAuthStateInvalid)
+	}
+	exchangeCtx, cancel := context.WithTimeout(ctx, oauthRequestTimeout)
+	defer cancel()
+	identity, err := p.Exchange(exchangeCtx, req.Code, st.Nonce, st.Verifier)
+	if err != nil {
+		s.logger.WithContext(ctx).Warn(logging.General, logging.OAuth, err.Error(), nil)
+		return nil, service_errors.Wrap(service_errors.ErrOAuthExchangeFailed, err)
+	}
+	return identity, nil
+}
+
+func (s *ExternalAuthService) findIdentity(ctx context.Context, provider string, subject string) (*models.ExternalIdentity, bool, error) {
+	var ei models.ExternalIdentity
+	err := db.Conn(ctx, s.database).
+		Where("provider = ? AND subject = ?", provider, subject).
+		First(&ei).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, false, nil
+	} else if err != nil {
+		s.logger.WithContext(ctx).Error(logging.Postgres, logging.Select, err.Error(), nil)
+		return nil, false, err
+	}
+	return &ei, true, nil
+}
+
+func (s *ExternalAuthService) Unlink(ctx context.Context, userId uint, provider string) error {
+	var ei models.ExternalIdentity
+	err := db.Conn(ctx, s.database).Where("user_id = ? AND provider = ?", userId, provider).First(&ei).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return service_errors.New(service_errors.ErrExternalIdentityNotFound)
+	} else if err != nil {
+		return err
+	}
+	if ei.Provisioned {
+		last, err := s.isLastLoginMethod(ctx, userId)
+		if err != nil {
+			return err
+		}
+		if last {
+			return service_errors.New(service_errors.ErrExternalIdentityLastLogin)
+		}
+	}
+	// Hard delete, so the same identity can be linked again without hitting the unique index.
+	err = db.Conn(ctx, s.database).Unscoped().Delete(&ei).Error
+	if err != nil {
+		s.logger.WithContext(ctx).Error(logging.Postgres, logging.Delete, err.Error(), nil)
+		return err
+	}
+	s.auditIdentity(ctx, audit.IdentityUnlinked, &ei)
+	return nil
+}
+
+// isLastLoginMethod reports whether a provisioned user would be locked out: such users never chose a password, so
+// they need a mobile number, a passkey or another external identity to sign in.
+func (s *ExternalAuthService) isLastLoginMethod(ctx context.Context, userId uint) (bool, error) {
+	var user models.User
+	err := db.Conn(ctx, s.database).Select("mobile_number").Where("id = ?", userId).First(&user).Error
+	if err != nil {
+		return false, err
+	}
+	if user.MobileNumber != "" {
+		return false, nil
+	}
+	var identities, credentials int64
+	err = db.Conn(ctx, s.database).Model(&models.ExternalIdentity{}).Where("user_id = ?", userId).Count(&identities).Error
+	if err != nil {
+		return false, err
+	}
+	err = db.Conn(ctx, s.database).Model(&models.WebAuthnCredential{}).Where("user_id = ?", userId).Count(&credentials).Error
+	if err != nil {
+		return false, err
+	}
+	return identities <= 1 && credentials == 0, nil
+}
+
+func (s *ExternalAuthService) link(ctx context.Context, userId uint, provider string, identity *oauth.Identity) error {
+	var exists bool
+	err := db.Conn(ctx, s.database).Model(&models.ExternalIdentity{}).
+		Select("count(*) > 0").
+		Where("user_id = ? AND provider = ?", userId, provider).
+		Find(&exists).Error
+	if err != nil {
+		s.logger.WithContext(ctx).Error(logging.Postgres, logging.Select, err.Error(), nil)
+		return err
+	}
+	if exists {
+		return service_errors.New(service_errors.ErrExternalIdentityLinked)
+	}
+	ei := models.ExternalIdentity{
+		UserId:   userId,
+		Provider: provider,
+		Subject:  identity.Subject,
+		Email:    identity.Email,
+	}
+	err = db.Conn(ctx, s.database).Create(&ei).Error
+	if err != nil {
+		s.logger.WithContext(ctx).Error(logging.Postgres, logging.Insert, err.Error(), nil)
+		return err
+	}
+	s.auditIdentity(ctx, audit.IdentityLinked, &ei)
+	return nil
+}
+
+// auditIdentity records a link or unlink of ei on behalf of its user.
+func (s *ExternalAuthService) auditIdentity(ctx context.Context, action audit.Action, ei *models.ExternalIdentity) {
+	event := AuditEvent{
+		Action:     action,
+		UserId:     ei.UserId,
+		TargetType: audit.TargetExternalIdentity,
+		TargetId:   ei.Provider,
+	}
+	identity := map[string]string{"provider": ei.Provider, "subject": ei.Subject}
+	if action == audit.IdentityUnlinked {
+		event.Before = identity
+	} else {
+		event.After = identity
+	}
+	s.audit.Log(ctx, event)
+}
+
+// provision creates a user with the default role for an identity seen for the first time.
+func (s *ExternalAuthService) provision(ctx context.Context, provider string, identity *oauth.Identity) (uint, error) {
+	u := models.User{
+		FirstName: truncateRunes(identity.FirstName, 15),
+		LastName:  truncateRunes(identity.LastName, 25),
+	}
+	if identity.Email != "" && identity.EmailVerified {
+		exists, err := s.users.ExistsByEmail(ctx, identity.Email)
+		if err != nil {
+			s.logger.WithContext(ctx).Error(logging.Postgres, logging.Select, err.Error(), nil)
+			return 0, err
+		}
+		if exists {
+			return 0, service_errors.New(service_errors.ErrEmailExists)
+		}
+		u.Email = identity.Email
+	}
+	username, err := s.generateUsername(ctx, provider)
+	if err != nil {
+		return 0, err
+	}
+	u.Username = username
+	hp, err := hashPassword(ctx, common.GeneratePassword())
+	if err != nil {
+		s.logger.WithContext(ctx).Error(logging.General, logging.HashPassword, err.Error(), nil)
+		return 0, err
+	}
+	u.Password = string(hp)
+	roleId, err := s.userService.getDefaultRole(ctx)
+	if err != nil {
+		s.logger.WithContext(ctx).Error(logging.Postgres, logging.DefaultRoleNotFound, err.Error(), nil)
+		return 0, err
+	}
+	err = s.uow.WithTransaction(ctx, func(ctx context.Context) error {
+		err := s.users.CreateWithRole(ctx, &u, roleId)
+		if err != nil {
+			return err
+		}
+		return s.identities.Create(ctx, &models.ExternalIdentity{
+			UserId:      u.ID,
+			Provider:    provider,
+			Subject:     identity.Subject,
+			Email:       identity.Email,
+			Provisioned: true,
+		})
+	})
+	if err != nil {
+		s.logger.WithContext(ctx).Error(logging.Postgres, logging.Rollback, err.Error(), nil)
+		return 0, err
+	}
+	s.userService.auditUser(ctx, audit.Register, u.ID, &u)
+	return u.ID, nil
+}
+
+func (s *ExternalAuthService) generateUsername(ctx context.Context, provider string) (string, error) {
+	prefix := truncateRunes(provider, 6)
+	for i := 0; i < usernameAttempts; i++ {
+		b := make([]byte, 6)
+		if _, err := rand.Read(b); err != nil {
+			return "", err
+		}
+		username := fmt.Sprintf("%s_%s", prefix, hex.EncodeToString(b))
+		exists, err := s.users.ExistsByUsername(ctx, username)
+		if err != nil {
+			s.logger.WithContext(ctx).Error(logging.Postgres, logging.Select, err.Error(), nil)
+			return "", err
+		}
+		if !exists {
+			return username, nil
+		}
+	}
+	return "", service_errors.New(service_errors.ErrUsernameExists)
+}
+
+func (s *ExternalAuthService) stateKey(state string) string {
+	return fmt.Sprintf("%s:%s", constants.RedisOAuthStateKey, state)
+}
+
+// popState returns the stored flow state and deletes it, so each state can be redeemed only once.
+func (s *ExternalAuthService) popState(ctx context.Context, state string) (*oauthState, error) {
+	key := s.stateKey(state)
+	st, err := cache.Get[oauthState](ctx, s.redisClient, key)
+	if errors.Is(err, redis.Nil) {
+		return nil, service_errors.New(service_errors.ErrOAuthStateInvalid)
+	} else if err != nil {
+		return nil, err
+	}
+	n, err := s.redisClient.WithContext(ctx).Del(key).Result()
+	if err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		return nil, service_errors.New(service_errors.ErrOAuthStateInvalid)
+	}
+	return &st, nil
+}
+
+func randomToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func truncateRunes(s string, n int) string {
+	r := []rune(s)
+	if len(r) > n {
+		return string(r[:n])
+	}
+	return s
+}
@@ -0,0 +1,13 @@
+package services
+
+import (
+	"base_structure/src/api/dto"
+	"context"
+)
+
+type ExternalAuthServiceIface interface {
+	AuthUrl(ctx context.Context, provider string, linkUserId uint) (*dto.OAuthAuthUrlResponse, error)
+	Callback(ctx context.Context, provider string, req *dto.OAuthCallbackRequest) (*dto.TokenDetail, error)
+	LinkCallback(ctx context.Context, userId uint, provider string, req *dto.OAuthCallbackRequest) error
+	Unlink(ctx context.Context, userId uint, provider string) error
+}
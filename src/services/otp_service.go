@@ -5,7 +5,10 @@ import (
 	"base_structure/src/constants"
 	"base_structure/src/data/cache"
 	"base_structure/src/pkg/logging"
+	"base_structure/src/pkg/metrics"
 	"base_structure/src/pkg/service_errors"
+	"context"
+	"errors"
 	"fmt"
 	"github.com/go-redis/redis/v7"
 	"time"
@@ -28,37 +31,44 @@ func NewOtpService(cfg *config.Config) *OtpService {
 	return &OtpService{Logger: logger, Cfg: cfg, RedisClient: redisClient}
 }
 
-func (s *OtpService) SetOtp(mobileNumber string, otp string) error {
+func (s *OtpService) SetOtp(ctx context.Context, mobileNumber string, otp string) error {
 	key := fmt.Sprintf("%s:%s", constants.RedisOtpDefaultKey, mobileNumber)
 	val := &OtpDto{Value: otp, Used: false}
-	res, err := cache.Get[OtpDto](s.RedisClient, key)
+	res, err := cache.Get[OtpDto](ctx, s.RedisClient, key)
 	if err == nil && !res.Used {
-		return &service_errors.ServiceError{EndUserMessage: service_errors.OtpExists}
+		return service_errors.New(service_errors.ErrOtpExists)
 	} else if err == nil && res.Used {
-		return &service_errors.ServiceError{EndUserMessage: service_errors.OtpUsed}
+		return service_errors.New(service_errors.ErrOtpUsed)
 	}
-	err = cache.Set(s.RedisClient, key, val, s.Cfg.Otp.ExpireTime*time.Second)
+	err = cache.Set(ctx, s.RedisClient, key, val, s.Cfg.Otp.ExpireTime*time.Second)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (s *OtpService) ValidateOtp(mobileNumber string, otp string) error {
+func (s *OtpService) ValidateOtp(ctx context.Context, mobileNumber string, otp string) error {
 	key := fmt.Sprintf("%s:%s", constants.RedisOtpDefaultKey, mobileNumber)
-	res, err := cache.Get[OtpDto](s.RedisClient, key)
+	res, err := cache.Get[OtpDto](ctx, s.RedisClient, key)
+	if errors.Is(err, redis.Nil) {
+		metrics.OtpValidations.WithLabelValues("expired").Inc()
+		return service_errors.Wrap(service_errors.ErrOtpExpired, err)
+	} else if err != nil {
+		metrics.OtpValidations.WithLabelValues("error").Inc()
+		return err
+	} else if res.Used {
+		metrics.OtpValidations.WithLabelValues("used").Inc()
+		return service_errors.New(service_errors.ErrOtpUsed)
+	} else if res.Value != otp {
+		metrics.OtpValidations.WithLabelValues("invalid").Inc()
+		return service_errors.New(service_errors.ErrOtpNotValid)
+	}
+	res.Used = true
+	err = cache.Set(ctx, s.RedisClient, key, res, s.Cfg.Otp.ExpireTime*time.Second)
 	if err != nil {
+		metrics.OtpValidations.WithLabelValues("error").Inc()
 		return err
-	} else if err == nil && res.Used {
-		return &service_errors.ServiceError{EndUserMessage: service_errors.OtpUsed}
-	} else if err == nil && !res.Used && res.Value != otp {
-		return &service_errors.ServiceError{EndUserMessage: service_errors.OtpNotValid}
-	} else if err == nil && !res.Used && res.Value == otp {
-		res.Used = true
-		err = cache.Set(s.RedisClient, key, res, s.Cfg.Otp.ExpireTime*time.Second)
-		if err != nil {
-			return err
-		}
 	}
+	metrics.OtpValidations.WithLabelValues("valid").Inc()
 	return nil
 }
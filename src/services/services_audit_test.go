@@ -0,0 +1,103 @@
+package services
+
+import (
+	"base_structure/src/api/dto"
+	"base_structure/src/config"
+	"base_structure/src/data/models"
+	"base_structure/src/data/repository"
+	"base_structure/src/pkg/actor"
+	"base_structure/src/pkg/audit"
+	"base_structure/src/pkg/logging"
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"testing"
+)
+
+type profile struct {
+	FirstName string
+	LastName  string
+	Password  string
+}
+
+func newTestAuditLogger(t *testing.T) (*AuditLogger, *gorm.DB) {
+	t.Helper()
+	cfg := newTestConfig()
+	cfg.Audit = config.AuditConfig{BufferSize: 8, BatchSize: 2, FlushInterval: 60}
+	database := newTestDb(t)
+	a := NewAuditLogger(cfg, database)
+	t.Cleanup(a.Close)
+	return a, database
+}
+
+func TestAuditLoggerWritesRecords(t *testing.T) {
+	a, database := newTestAuditLogger(t)
+	request := audit.WithRequestInfo(context.Background(), audit.RequestInfo{
+		Ip: "10.0.0.1", UserAgent: "test-agent", RequestId: "req-1",
+	})
+
+	a.Log(actor.WithUser(request, 5), AuditEvent{
+		Action:     audit.Register,
+		TargetType: audit.TargetUser,
+		TargetId:   "9",
+		Before:     profile{FirstName: "Ali", LastName: "Test", Password: "old"},
+		After:      profile{FirstName: "Reza", LastName: "Test", Password: "new"},
+	})
+	a.Log(request, AuditEvent{Action: audit.Login, UserId: 9})
+	a.Log(context.Background(), AuditEvent{Action: audit.LoginFailed})
+	a.Close()
+
+	var records []models.AuditLog
+	require.NoError(t, database.Order("id").Find(&records).Error)
+	require.Len(t, records, 3)
+
+	assert.Equal(t, 5, records[0].ActorId)
+	assert.Equal(t, "10.0.0.1", records[0].Ip)
+	assert.Equal(t, "test-agent", records[0].UserAgent)
+	assert.Equal(t, "req-1", records[0].RequestId)
+	var before, after map[string]string
+	require.NoError(t, json.Unmarshal([]byte(records[0].Before), &before))
+	require.NoError(t, json.Unmarshal([]byte(records[0].After), &after))
+	assert.Equal(t, map[string]string{"FirstName": "Ali", "Password": "[REDACTED]"}, before)
+	assert.Equal(t, map[string]string{"FirstName": "Reza", "Password": "[REDACTED]"}, after)
+
+	assert.Equal(t, 9, records[1].ActorId)
+	assert.Equal(t, actor.Unknown, records[2].ActorId)
+	assert.Empty(t, records[2].Before)
+}
+
+func TestAuditLoggerWritesSynchronouslyAfterClose(t *testing.T) {
+	a, database := newTestAuditLogger(t)
+	a.Close()
+
+	a.Log(context.Background(), AuditEvent{Action: audit.Logout, UserId: 3})
+	var count int64
+	require.NoError(t, database.Model(&models.AuditLog{}).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestAuditLogServiceList(t *testing.T) {
+	a, database := newTestAuditLogger(t)
+	for i := 0; i < 3; i++ {
+		a.Log(context.Background(), AuditEvent{Action: audit.LoginFailed, UserId: 7})
+	}
+	a.Log(context.Background(), AuditEvent{Action: audit.Login, UserId: 7})
+	a.Close()
+	svc := &AuditLogService{
+		logger: logging.NewLogger(config.GetConfig()),
+		logs:   repository.NewRepository[models.AuditLog](database),
+	}
+	actorId := 7
+
+	res, err := svc.List(context.Background(), &dto.AuditLogFilter{
+		ActorId: &actorId, Action: string(audit.LoginFailed), PageNumber: 1, PageSize: 2,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), res.TotalRows)
+	assert.Equal(t, 2, res.TotalPages)
+	require.Len(t, res.Items, 2)
+	assert.Greater(t, res.Items[0].Id, res.Items[1].Id)
+	assert.Equal(t, string(audit.LoginFailed), res.Items[0].Action)
+}
@@ -0,0 +1,387 @@
+package services
+
+import (
+	"base_structure/src/api/dto"
+	"base_structure/src/config"
+	"base_structure/src/constants"
+	"base_structure/src/data/db"
+	"base_structure/src/data/models"
+	"base_structure/src/data/repository"
+	"base_structure/src/pkg/audit"
+	"base_structure/src/pkg/logging"
+	"base_structure/src/pkg/oauth"
+	"base_structure/src/pkg/service_errors"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+)
+
+/* ------------------------------------------------------------------------- */
+/* Mock identity provider (OIDC discovery + plain OAuth2 user info)           */
+
+const (
+	mockClientId     = "client"
+	mockClientSecret = "secret"
+	mockKeyId        = "test-key"
+)
+
+type mockGrant struct {
+	subject   string
+	email     string
+	nonce     string
+	challenge string
+}
+
+type mockIdentityProvider struct {
+	t      *testing.T
+	srv    *httptest.Server
+	key    *rsa.PrivateKey
+	mu     sync.Mutex
+	grants map[string]mockGrant
+	tokens map[string]mockGrant
+	// nonceOverride makes the provider sign id tokens with a different nonce.
+	nonceOverride string
+}
+
+func newMockIdentityProvider(t *testing.T) *mockIdentityProvider {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	m := &mockIdentityProvider{t: t, key: key, grants: map[string]mockGrant{}, tokens: map[string]mockGrant{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", m.discovery)
+	mux.HandleFunc("/jwks", m.jwks)
+	mux.HandleFunc("/token", m.token)
+	mux.HandleFunc("/userinfo", m.userInfo)
+	m.srv = httptest.NewServer(mux)
+	t.Cleanup(m.srv.Close)
+	return m
+}
+
+func (m *mockIdentityProvider) writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func (m *mockIdentityProvider) discovery(w http.ResponseWriter, _ *http.Request) {
+	m.writeJSON(w, map[string]any{
+		"issuer":                                m.srv.URL,
+		"authorization_endpoint":                m.srv.URL + "/authorize",
+		"token_endpoint":                        m.srv.URL + "/token",
+		"jwks_uri":                              m.srv.URL + "/jwks",
+		"userinfo_endpoint":                     m.srv.URL + "/userinfo",
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+	})
+}
+
+func (m *mockIdentityProvider) jwks(w http.ResponseWriter, _ *http.Request) {
+	m.writeJSON(w, map[string]any{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": mockKeyId,
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(m.key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(m.key.E)).Bytes()),
+		}},
+	})
+}
+
+// authorize plays the browser redirect: it accepts the auth URL and returns a code for the given user.
+func (m *mockIdentityProvider) authorize(authUrl string, subject string, email string) (code string, state string) {
+	u, err := url.Parse(authUrl)
+	require.NoError(m.t, err)
+	q := u.Query()
+	require.Equal(m.t, mockClientId, q.Get("client_id"))
+	require.Equal(m.t, "S256", q.Get("code_challenge_method"))
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	code = fmt.Sprintf("code-%d", len(m.grants)+1)
+	m.grants[code] = mockGrant{subject: subject, email: email, nonce: q.Get("nonce"), challenge: q.Get("code_challenge")}
+	return code, q.Get("state")
+}
+
+func (m *mockIdentityProvider) token(w http.ResponseWriter, r *http.Request) {
+	_ = r.ParseForm()
+	m.mu.Lock()
+	grant, ok := m.grants[r.Form.Get("code")]
+	delete(m.grants, r.Form.Get("code"))
+	m.mu.Unlock()
+	sum := sha256.Sum256([]byte(r.Form.Get("code_verifier")))
+	if !ok || base64.RawURLEncoding.EncodeToString(sum[:]) != grant.challenge {
+		w.WriteHeader(http.StatusBadRequest)
+		m.writeJSON(w, map[string]string{"error": "invalid_grant"})
+		return
+	}
+	nonce := grant.nonce
+	if m.nonceOverride != "" {
+		nonce = m.nonceOverride
+	}
+	idToken := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":            m.srv.URL,
+		"sub":            grant.subject,
+		"aud":            mockClientId,
+		"exp":            time.Now().Add(time.Hour).Unix(),
+		"iat":            time.Now().Unix(),
+		"nonce":          nonce,
+		"email":          grant.email,
+		"email_verified": true,
+		"given_name":     "Sara",
+		"family_name":    "Ahmadi",
+	})
+	idToken.Header["kid"] = mockKeyId
+	raw, err := idToken.SignedString(m.key)
+	require.NoError(m.t, err)
+	accessToken := "at-" + r.Form.Get("code")
+	m.mu.Lock()
+	m.tokens[accessToken] = grant
+	m.mu.Unlock()
+	m.writeJSON(w, map[string]any{
+		"access_token": accessToken,
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     raw,
+	})
+}
+
+func (m *mockIdentityProvider) userInfo(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	grant, ok := m.tokens[r.Header.Get("Authorization")[len("Bearer "):]]
+	m.mu.Unlock()
+	if !ok {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	var id int64
+	_, _ = fmt.Sscan(grant.subject, &id)
+	m.writeJSON(w, map[string]any{"id": id, "email": grant.email, "name": "Reza Karimi"})
+}
+
+/* ------------------------------------------------------------------------- */
+/* Test bootstrap                                                            */
+
+func newTestExternalAuthService(t *testing.T) (*ExternalAuthService, *mockIdentityProvider, *gorm.DB) {
+	t.Helper()
+	cfg := newTestConfig()
+	cfg.OAuth.StateExpireTime = 60
+	idp := newMockIdentityProvider(t)
+	database := newTestDb(t)
+	require.NoError(t, database.Create(&models.Role{Name: constants.DefaultRoleName}).Error)
+	logger := logging.NewLogger(config.GetConfig())
+	auditLogger := &fakeAuditLogger{}
+	return &ExternalAuthService{
+		logger:       logger,
+		cfg:          cfg,
+		redisClient:  newTestRedis(t),
+		database:     database,
+		tokenService: &TokenService{cfg: cfg},
+		userService: &UserService{
+			cfg:    cfg,
+			logger: logger,
+			audit:  auditLogger,
+			users:  repository.NewUserRepository(database),
+			roles:  repository.NewRoleRepository(database),
+		},
+		audit:      auditLogger,
+		uow:        db.NewUnitOfWork(database),
+		users:      repository.NewUserRepository(database),
+		identities: repository.NewRepository[models.ExternalIdentity](database),
+		providers: map[string]oauth.Provider{
+			"mock": oauth.NewProvider("mock", config.OAuthProviderConfig{
+				Issuer:       idp.srv.URL,
+				ClientId:     mockClientId,
+				ClientSecret: mockClientSecret,
+				RedirectUrl:  "http://localhost/callback",
+			}),
+			"plain": oauth.NewProvider("plain", config.OAuthProviderConfig{
+				ClientId:     mockClientId,
+				ClientSecret: mockClientSecret,
+				RedirectUrl:  "http://localhost/callback",
+				AuthUrl:      idp.srv.URL + "/authorize",
+				TokenUrl:     idp.srv.URL + "/token",
+				UserInfoUrl:  idp.srv.URL + "/userinfo",
+				SubjectField: "id",
+			}),
+		},
+	}, idp, database
+}
+
+func login(t *testing.T, svc *ExternalAuthService, idp *mockIdentityProvider, provider string, subject string) (*dto.TokenDetail, error) {
+	t.Helper()
+	return svc.Callback(context.Background(), provider, authorize(t, svc, idp, provider, 0, subject))
+}
+
+// link runs a link flow started by userId and completed by callerId.
+func link(t *testing.T, svc *ExternalAuthService, idp *mockIdentityProvider, provider string, userId uint, callerId uint, subject string) error {
+	t.Helper()
+	return svc.LinkCallback(context.Background(), callerId, provider, authorize(t, svc, idp, provider, userId, subject))
+}
+
+func authorize(t *testing.T, svc *ExternalAuthService, idp *mockIdentityProvider, provider string, linkUserId uint, subject string) *dto.OAuthCallbackRequest {
+	t.Helper()
+	res, err := svc.AuthUrl(context.Background(), provider, linkUserId)
+	require.NoError(t, err)
+	code, state := idp.authorize(res.AuthUrl, subject, subject+"@example.com")
+	return &dto.OAuthCallbackRequest{Code: code, State: state}
+}
+
+func assertServiceError(t *testing.T, err error, msg string) {
+	t.Helper()
+	var se *service_errors.ServiceError
+	require.True(t, errors.As(err, &se), "expected service error, got %v", err)
+	assert.Equal(t, msg, se.EndUserMessage)
+}
+
+/* ------------------------------------------------------------------------- */
+/* Login & provisioning                                                      */
+
+func TestExternalLoginProvisionsUserOnce(t *testing.T) {
+	svc, idp, database := newTestExternalAuthService(t)
+
+	token, err := login(t, svc, idp, "mock", "1001")
+	require.NoError(t, err)
+	assert.NotEmpty(t, token.AccessToken)
+
+	var ei models.ExternalIdentity
+	require.NoError(t, database.Preload("User").Where("provider = ? AND subject = ?", "mock", "1001").First(&ei).Error)
+	assert.True(t, ei.Provisioned)
+	assert.Equal(t, "1001@example.com", ei.User.Email)
+	assert.Equal(t, "Sara", ei.User.FirstName)
+	var roles int64
+	database.Model(&models.RoleUser{}).Where("user_id = ?", ei.UserId).Count(&roles)
+	assert.Equal(t, int64(1), roles)
+
+	_, err = login(t, svc, idp, "mock", "1001")
+	require.NoError(t, err)
+	var users int64
+	database.Model(&models.User{}).Count(&users)
+	assert.Equal(t, int64(1), users)
+	assert.Equal(t, []audit.Action{audit.Register, audit.Login, audit.Login}, svc.audit.(*fakeAuditLogger).actions())
+}
+
+func TestExternalLoginWithPlainOAuth2Provider(t *testing.T) {
+	svc, idp, database := newTestExternalAuthService(t)
+
+	_, err := login(t, svc, idp, "plain", "42")
+	require.NoError(t, err)
+
+	var ei models.ExternalIdentity
+	require.NoError(t, database.Preload("User").Where("provider = ?", "plain").First(&ei).Error)
+	assert.Equal(t, "42", ei.Subject)
+	assert.Equal(t, "Reza", ei.User.FirstName)
+	assert.Empty(t, ei.User.Email, "unverified e-mails are not copied to the user")
+}
+
+func TestExternalLoginRejectsReplayedState(t *testing.T) {
+	ctx := context.Background()
+	svc, idp, _ := newTestExternalAuthService(t)
+
+	res, err := svc.AuthUrl(ctx, "mock", 0)
+	require.NoError(t, err)
+	code, state := idp.authorize(res.AuthUrl, "1001", "a@example.com")
+	_, err = svc.Callback(ctx, "mock", &dto.OAuthCallbackRequest{Code: code, State: state})
+	require.NoError(t, err)
+
+	_, err = svc.Callback(ctx, "mock", &dto.OAuthCallbackRequest{Code: code, State: state})
+	assertServiceError(t, err, service_errors.OAuthStateInvalid)
+
+	auditLogger := svc.audit.(*fakeAuditLogger)
+	assert.Equal(t, []audit.Action{audit.Register, audit.Login, audit.LoginFailed}, auditLogger.actions())
+	assert.Equal(t, audit.TargetExternalIdentity, auditLogger.events[2].TargetType)
+	assert.Equal(t, "mock", auditLogger.events[2].TargetId)
+}
+
+func TestExternalLoginRejectsNonceMismatch(t *testing.T) {
+	svc, idp, _ := newTestExternalAuthService(t)
+	idp.nonceOverride = "forged"
+
+	_, err := login(t, svc, idp, "mock", "1001")
+	assertServiceError(t, err, service_errors.OAuthExchangeFailed)
+}
+
+func TestExternalLoginUnknownProvider(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _ := newTestExternalAuthService(t)
+
+	_, err := svc.AuthUrl(ctx, "unknown", 0)
+	assertServiceError(t, err, service_errors.OAuthProviderNotFound)
+}
+
+/* ------------------------------------------------------------------------- */
+/* Linking & unlinking                                                       */
+
+func TestExternalIdentityLinkAndUnlink(t *testing.T) {
+	ctx := context.Background()
+	svc, idp, database := newTestExternalAuthService(t)
+	user := createTestUser(t, database, "linked_user")
+
+	require.NoError(t, link(t, svc, idp, "mock", user.ID, user.ID, "2002"))
+	var ei models.ExternalIdentity
+	require.NoError(t, database.Where("user_id = ? AND provider = ?", user.ID, "mock").First(&ei).Error)
+	assert.False(t, ei.Provisioned)
+
+	require.NoError(t, svc.Unlink(ctx, user.ID, "mock"))
+	assertServiceError(t, svc.Unlink(ctx, user.ID, "mock"), service_errors.ExternalIdentityNotFound)
+
+	require.NoError(t, link(t, svc, idp, "mock", user.ID, user.ID, "2002"), "an unlinked identity can be linked again")
+
+	auditLogger := svc.audit.(*fakeAuditLogger)
+	assert.Equal(t, []audit.Action{audit.IdentityLinked, audit.IdentityUnlinked, audit.IdentityLinked}, auditLogger.actions())
+	assert.Equal(t, user.ID, auditLogger.events[1].UserId)
+	assert.Equal(t, map[string]string{"provider": "mock", "subject": "2002"}, auditLogger.events[1].Before)
+}
+
+func TestExternalIdentityLinkedToAnotherUser(t *testing.T) {
+	svc, idp, database := newTestExternalAuthService(t)
+	_, err := login(t, svc, idp, "mock", "3003")
+	require.NoError(t, err)
+	other := createTestUser(t, database, "other_user")
+
+	assertServiceError(t, link(t, svc, idp, "mock", other.ID, other.ID, "3003"), service_errors.ExternalIdentityLinked)
+}
+
+func TestExternalIdentityLinkOnlyCompletesForItsUser(t *testing.T) {
+	ctx := context.Background()
+	svc, idp, database := newTestExternalAuthService(t)
+	victim := createTestUser(t, database, "victim_user")
+	attacker := createTestUser(t, database, "attacker_user")
+
+	_, err := svc.Callback(ctx, "mock", authorize(t, svc, idp, "mock", victim.ID, "5005"))
+	assertServiceError(t, err, service_errors.OAuthStateInvalid)
+	assertServiceError(t, link(t, svc, idp, "mock", victim.ID, attacker.ID, "5005"), service_errors.OAuthStateInvalid)
+	assertServiceError(t, link(t, svc, idp, "mock", 0, attacker.ID, "5005"), service_errors.OAuthStateInvalid)
+
+	var identities int64
+	database.Model(&models.ExternalIdentity{}).Count(&identities)
+	assert.Zero(t, identities)
+}
+
+func TestExternalIdentityUnlinkLastLoginMethod(t *testing.T) {
+	ctx := context.Background()
+	svc, idp, database := newTestExternalAuthService(t)
+	_, err := login(t, svc, idp, "mock", "4004")
+	require.NoError(t, err)
+	var ei models.ExternalIdentity
+	require.NoError(t, database.Where("subject = ?", "4004").First(&ei).Error)
+
+	assertServiceError(t, svc.Unlink(ctx, ei.UserId, "mock"), service_errors.ExternalIdentityLastLogin)
+
+	require.NoError(t, link(t, svc, idp, "plain", ei.UserId, ei.UserId, "4005"))
+	require.NoError(t, svc.Unlink(ctx, ei.UserId, "mock"))
+}
@@ -0,0 +1,229 @@
+package services
+
+import (
+	"base_structure/src/api/dto"
+	"base_structure/src/config"
+	"base_structure/src/constants"
+	"base_structure/src/data/models"
+	"base_structure/src/data/repository"
+	"base_structure/src/pkg/audit"
+	"base_structure/src/pkg/logging"
+	"base_structure/src/pkg/service_errors"
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+	"testing"
+)
+
+/* ------------------------------------------------------------------------- */
+/* In-memory repositories                                                    */
+
+// fakeUserRepository keeps users in memory. Methods UserService doesn't use are left to the embedded nil interface.
+type fakeUserRepository struct {
+	repository.UserRepository
+	users    []*models.User
+	roleUser map[uint]uint
+}
+
+func newFakeUserRepository() *fakeUserRepository {
+	return &fakeUserRepository{roleUser: map[uint]uint{}}
+}
+
+func (r *fakeUserRepository) find(match func(u *models.User) bool) *models.User {
+	for _, u := range r.users {
+		if match(u) {
+			return u
+		}
+	}
+	return nil
+}
+
+func (r *fakeUserRepository) FindByUsername(_ context.Context, username string) (*models.User, error) {
+	if u := r.find(func(u *models.User) bool { return u.Username == username }); u != nil {
+		return u, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeUserRepository) FindByMobileNumber(_ context.Context, mobileNumber string) (*models.User, error) {
+	if u := r.find(func(u *models.User) bool { return u.MobileNumber == mobileNumber }); u != nil {
+		return u, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeUserRepository) ExistsByUsername(_ context.Context, username string) (bool, error) {
+	return r.find(func(u *models.User) bool { return u.Username == username }) != nil, nil
+}
+
+func (r *fakeUserRepository) ExistsByEmail(_ context.Context, email string) (bool, error) {
+	return r.find(func(u *models.User) bool { return u.Email == email }) != nil, nil
+}
+
+func (r *fakeUserRepository) ExistsByMobileNumber(_ context.Context, mobileNumber string) (bool, error) {
+	return r.find(func(u *models.User) bool { return u.MobileNumber == mobileNumber }) != nil, nil
+}
+
+func (r *fakeUserRepository) CreateWithRole(_ context.Context, user *models.User, roleId uint) error {
+	user.ID = uint(len(r.users) + 1)
+	user.RoleUsers = &[]models.RoleUser{{RoleId: roleId, Role: models.Role{Name: "default"}}}
+	r.users = append(r.users, user)
+	r.roleUser[user.ID] = roleId
+	return nil
+}
+
+type fakeRoleRepository struct {
+	repository.RoleRepository
+	roles map[string]uint
+}
+
+func (r *fakeRoleRepository) FindByName(_ context.Context, name string) (*models.Role, error) {
+	id, ok := r.roles[name]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	role := &models.Role{Name: name}
+	role.ID = id
+	return role, nil
+}
+
+// fakeUnitOfWork runs the work without a transaction; the fakes have nothing to roll back.
+type fakeUnitOfWork struct{}
+
+func (fakeUnitOfWork) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// countingUnitOfWork runs the work without a transaction and counts how often it was asked to.
+type countingUnitOfWork struct {
+	calls int
+}
+
+func (u *countingUnitOfWork) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	u.calls++
+	return fn(ctx)
+}
+
+// fakeAuditLogger keeps the logged events for inspection.
+type fakeAuditLogger struct {
+	events []AuditEvent
+}
+
+func (a *fakeAuditLogger) Log(_ context.Context, event AuditEvent) {
+	a.events = append(a.events, event)
+}
+
+func (a *fakeAuditLogger) actions() []audit.Action {
+	actions := make([]audit.Action, 0, len(a.events))
+	for _, e := range a.events {
+		actions = append(actions, e.Action)
+	}
+	return actions
+}
+
+func newTestUserService(roles map[string]uint) (*UserService, *fakeUserRepository) {
+	svc, users, _ := newTestUserServiceWithAudit(roles)
+	return svc, users
+}
+
+func newTestUserServiceWithAudit(roles map[string]uint) (*UserService, *fakeUserRepository, *fakeAuditLogger) {
+	cfg := newTestConfig()
+	users := newFakeUserRepository()
+	auditLogger := &fakeAuditLogger{}
+	return &UserService{
+		logger:       logging.NewLogger(config.GetConfig()),
+		cfg:          cfg,
+		tokenService: &TokenService{cfg: cfg},
+		audit:        auditLogger,
+		uow:          fakeUnitOfWork{},
+		users:        users,
+		roles:        &fakeRoleRepository{roles: roles},
+	}, users, auditLogger
+}
+
+/* ------------------------------------------------------------------------- */
+/* Tests                                                                     */
+
+func TestRegisterByUsernameAndLogin(t *testing.T) {
+	ctx := context.Background()
+	svc, users, auditLogger := newTestUserServiceWithAudit(map[string]uint{constants.DefaultRoleName: 7})
+
+	err := svc.RegisterByUsername(ctx, &dto.RegisterByUsernameRequest{
+		Username: "ali", FirstName: "Ali", LastName: "Test", Email: "ali@example.com", Password: "Secret123!",
+	})
+	require.NoError(t, err)
+	require.Len(t, users.users, 1)
+	assert.Equal(t, uint(7), users.roleUser[users.users[0].ID])
+	assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(users.users[0].Password), []byte("Secret123!")))
+
+	token, err := svc.LoginByUsername(ctx, &dto.LoginByUsernameRequest{Username: "ali", Password: "Secret123!"})
+	require.NoError(t, err)
+	assert.NotEmpty(t, token.AccessToken)
+
+	_, err = svc.LoginByUsername(ctx, &dto.LoginByUsernameRequest{Username: "ali", Password: "wrong"})
+	var se *service_errors.ServiceError
+	require.True(t, errors.As(err, &se))
+	assert.Equal(t, service_errors.InvalidCredentials, se.EndUserMessage)
+
+	_, err = svc.LoginByUsername(ctx, &dto.LoginByUsernameRequest{Username: "nobody", Password: "Secret123!"})
+	assert.True(t, errors.Is(err, gorm.ErrRecordNotFound))
+
+	assert.Equal(t, []audit.Action{audit.Register, audit.Login, audit.LoginFailed, audit.LoginFailed}, auditLogger.actions())
+	assert.Equal(t, users.users[0].ID, auditLogger.events[2].UserId)
+	assert.Equal(t, "nobody", auditLogger.events[3].TargetId)
+}
+
+func TestRegisterByUsernameRejectsDuplicates(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newTestUserService(map[string]uint{constants.DefaultRoleName: 1})
+	req := &dto.RegisterByUsernameRequest{Username: "ali", Email: "ali@example.com", Password: "Secret123!"}
+	require.NoError(t, svc.RegisterByUsername(ctx, req))
+
+	var se *service_errors.ServiceError
+	err := svc.RegisterByUsername(ctx, &dto.RegisterByUsernameRequest{Username: "reza", Email: "ali@example.com"})
+	require.True(t, errors.As(err, &se))
+	assert.Equal(t, service_errors.EmailExists, se.EndUserMessage)
+
+	err = svc.RegisterByUsername(ctx, &dto.RegisterByUsernameRequest{Username: "ali", Email: "other@example.com"})
+	require.True(t, errors.As(err, &se))
+	assert.Equal(t, service_errors.UsernameExists, se.EndUserMessage)
+}
+
+func TestRegisterByUsernameWithoutDefaultRole(t *testing.T) {
+	ctx := context.Background()
+	svc, users := newTestUserService(nil)
+
+	err := svc.RegisterByUsername(ctx, &dto.RegisterByUsernameRequest{Username: "ali", Password: "Secret123!"})
+	assert.True(t, errors.Is(err, gorm.ErrRecordNotFound))
+	assert.Empty(t, users.users)
+}
+
+func TestRegisterLoginByMobileNumberRunsInOneUnitOfWork(t *testing.T) {
+	ctx := context.Background()
+	svc, users, auditLogger := newTestUserServiceWithAudit(map[string]uint{constants.DefaultRoleName: 1})
+	uow := &countingUnitOfWork{}
+	svc.uow = uow
+	svc.otpService = &OtpService{Logger: svc.logger, Cfg: svc.cfg, RedisClient: newTestRedis(t)}
+	require.NoError(t, svc.otpService.SetOtp(ctx, "09123456789", "123456"))
+
+	token, err := svc.RegisterLoginByMobileNumber(ctx, &dto.RegisterLoginByMobileRequest{
+		MobileNumber: "09123456789", Otp: "123456",
+	})
+	require.NoError(t, err)
+	assert.NotEmpty(t, token.AccessToken)
+	require.Len(t, users.users, 1)
+	assert.Equal(t, 1, uow.calls)
+
+	_, err = svc.RegisterLoginByMobileNumber(ctx, &dto.RegisterLoginByMobileRequest{
+		MobileNumber: "09123456789", Otp: "123456",
+	})
+	var se *service_errors.ServiceError
+	require.True(t, errors.As(err, &se))
+	assert.Equal(t, service_errors.OtpUsed, se.EndUserMessage)
+	assert.Equal(t, 2, uow.calls)
+	assert.Equal(t, []audit.Action{audit.Register, audit.Login, audit.OtpFailed}, auditLogger.actions())
+	assert.Equal(t, "09123456789", auditLogger.events[2].TargetId)
+}
@@ -0,0 +1,363 @@
+package services
+
+import (
+	"base_structure/src/api/dto"
+	"base_structure/src/config"
+	"base_structure/src/data/models"
+	"base_structure/src/data/repository"
+	"base_structure/src/pkg/audit"
+	"base_structure/src/pkg/logging"
+	"base_structure/src/pkg/service_errors"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/glebarez/sqlite"
+	"github.com/go-redis/redis/v7"
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/protocol/webauthncbor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"strconv"
+	"testing"
+)
+
+/* ------------------------------------------------------------------------- */
+/* Test bootstrap                                                            */
+
+const (
+	testRpId   = "localhost"
+	testOrigin = "https://localhost"
+)
+
+func newTestConfig() *config.Config {
+	return &config.Config{
+		Logger: config.LoggerConfig{Logger: "zap"},
+		Jwt: config.JwtConfig{
+			Secret: "secret", RefreshSecret: "refresh",
+			AccessTokenExpireDuration: 60, RefreshTokenExpireDuration: 60,
+		},
+		WebAuthn: config.WebAuthnConfig{
+			RPID: testRpId, RPDisplayName: "test", RPOrigins: []string{testOrigin},
+			ChallengeExpireTime: 60,
+		},
+	}
+}
+
+// newTestDb opens an isolated in-memory SQLite database with the user schema migrated.
+func newTestDb(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())
+	database, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, database.AutoMigrate(
+		&models.User{}, &models.Role{}, &models.RoleUser{},
+		&models.WebAuthnCredential{}, &models.ExternalIdentity{}, &models.AuditLog{},
+	))
+	t.Cleanup(func() {
+		sqlDb, _ := database.DB()
+		_ = sqlDb.Close()
+	})
+	return database
+}
+
+func newTestRedis(t *testing.T) *redis.Client {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	return redis.NewClient(&redis.Options{Addr: mr.Addr()})
+}
+
+func createTestUser(t *testing.T, database *gorm.DB, username string) *models.User {
+	t.Helper()
+	var role models.Role
+	require.NoError(t, database.Where(models.Role{Name: "default"}).FirstOrCreate(&role).Error)
+	u := models.User{Username: username, FirstName: "Ali", LastName: "Test", Password: "x"}
+	require.NoError(t, database.Create(&u).Error)
+	require.NoError(t, database.Create(&models.RoleUser{RoleId: role.ID, UserId: u.ID}).Error)
+	return &u
+}
+
+func newTestWebAuthnService(t *testing.T) (*WebAuthnService, *gorm.DB) {
+	t.Helper()
+	cfg := newTestConfig()
+	wa, err := newWebAuthn(cfg)
+	require.NoError(t, err)
+	database := newTestDb(t)
+	return &WebAuthnService{
+		logger:       logging.NewLogger(config.GetConfig()),
+		cfg:          cfg,
+		redisClient:  newTestRedis(t),
+		database:     database,
+		tokenService: &TokenService{cfg: cfg},
+		audit:        &fakeAuditLogger{},
+		users:        repository.NewUserRepository(database),
+		webAuthn:     wa,
+	}, database
+}
+
+/* ------------------------------------------------------------------------- */
+/* Software authenticator (packed "none" attestation, ES256)                  */
+
+type softAuthenticator struct {
+	key          *ecdsa.PrivateKey
+	credentialId []byte
+	userHandle   []byte
+	signCount    uint32
+}
+
+func newSoftAuthenticator(t *testing.T) *softAuthenticator {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	id := make([]byte, 32)
+	_, _ = rand.Read(id)
+	return &softAuthenticator{key: key, credentialId: id}
+}
+
+func b64(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func (a *softAuthenticator) clientData(t *testing.T, typ string, challenge string) []byte {
+	t.Helper()
+	b, err := json.Marshal(map[string]string{"type": typ, "challenge": challenge, "origin": testOrigin})
+	require.NoError(t, err)
+	return b
+}
+
+func (a *softAuthenticator) authData(flags protocol.AuthenticatorFlags, attested []byte) []byte {
+	rpIdHash := sha256.Sum256([]byte(testRpId))
+	counter := make([]byte, 4)
+	binary.BigEndian.PutUint32(counter, a.signCount)
+	data := append(rpIdHash[:], byte(flags))
+	data = append(data, counter...)
+	return append(data, attested...)
+}
+
+func (a *softAuthenticator) register(t *testing.T, creation *protocol.CredentialCreation) json.RawMessage {
+	t.Helper()
+	coseKey, err := webauthncbor.Marshal(map[int]interface{}{
+		1:  2,  // kty: EC2
+		3:  -7, // alg: ES256
+		-1: 1,  // crv: P-256
+		-2: a.key.X.FillBytes(make([]byte, 32)),
+		-3: a.key.Y.FillBytes(make([]byte, 32)),
+	})
+	require.NoError(t, err)
+	idLen := make([]byte, 2)
+	binary.BigEndian.PutUint16(idLen, uint16(len(a.credentialId)))
+	attested := append(make([]byte, 16), idLen...)
+	attested = append(attested, a.credentialId...)
+	attested = append(attested, coseKey...)
+	flags := protocol.FlagUserPresent | protocol.FlagUserVerified | protocol.FlagAttestedCredentialData
+	attObj, err := webauthncbor.Marshal(map[string]interface{}{
+		"fmt":      "none",
+		"attStmt":  map[string]interface{}{},
+		"authData": a.authData(flags, attested),
+	})
+	require.NoError(t, err)
+	body, err := json.Marshal(map[string]interface{}{
+		"id":    b64(a.credentialId),
+		"rawId": b64(a.credentialId),
+		"type":  "public-key",
+		"response": map[string]string{
+			"clientDataJSON":    b64(a.clientData(t, "webauthn.create", creation.Response.Challenge.String())),
+			"attestationObject": b64(attObj),
+		},
+	})
+	require.NoError(t, err)
+	return body
+}
+
+func (a *softAuthenticator) assert(t *testing.T, assertion *protocol.CredentialAssertion) json.RawMessage {
+	t.Helper()
+	a.signCount++
+	authData := a.authData(protocol.FlagUserPresent|protocol.FlagUserVerified, nil)
+	clientData := a.clientData(t, "webauthn.get", assertion.Response.Challenge.String())
+	clientDataHash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(append([]byte{}, authData...), clientDataHash[:]...))
+	sig, err := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	require.NoError(t, err)
+	response := map[string]string{
+		"clientDataJSON":    b64(clientData),
+		"authenticatorData": b64(authData),
+		"signature":         b64(sig),
+	}
+	if a.userHandle != nil {
+		response["userHandle"] = b64(a.userHandle)
+	}
+	body, err := json.Marshal(map[string]interface{}{
+		"id":       b64(a.credentialId),
+		"rawId":    b64(a.credentialId),
+		"type":     "public-key",
+		"response": response,
+	})
+	require.NoError(t, err)
+	return body
+}
+
+/* ------------------------------------------------------------------------- */
+/* Ceremonies                                                                */
+
+func TestWebAuthnRegistrationAndLogin(t *testing.T) {
+	ctx := context.Background()
+	svc, database := newTestWebAuthnService(t)
+	user := createTestUser(t, database, "passkey_user")
+	authenticator := newSoftAuthenticator(t)
+
+	creation, err := svc.BeginRegistration(ctx, user.ID)
+	require.NoError(t, err)
+	err = svc.FinishRegistration(ctx, user.ID, &dto.WebAuthnFinishRegistrationRequest{
+		Name:       "laptop",
+		Credential: authenticator.register(t, creation),
+	})
+	require.NoError(t, err)
+
+	var stored models.WebAuthnCredential
+	require.NoError(t, database.Where("user_id = ?", user.ID).First(&stored).Error)
+	assert.Equal(t, authenticator.credentialId, stored.CredentialId)
+	assert.Equal(t, "laptop", stored.Name)
+
+	begin, err := svc.BeginLogin(ctx, &dto.WebAuthnBeginLoginRequest{Username: user.Username})
+	require.NoError(t, err)
+	token, err := svc.FinishLogin(ctx, &dto.WebAuthnFinishLoginRequest{
+		CeremonyId: begin.CeremonyId,
+		Credential: authenticator.assert(t, begin.Options),
+	})
+	require.NoError(t, err)
+	assert.NotEmpty(t, token.AccessToken)
+	assert.NotEmpty(t, token.RefreshToken)
+
+	require.NoError(t, database.First(&stored, stored.ID).Error)
+	assert.Equal(t, uint32(1), stored.SignCount)
+	assert.Equal(t, []audit.Action{audit.Login}, svc.audit.(*fakeAuditLogger).actions())
+}
+
+func TestWebAuthnChallengeIsSingleUse(t *testing.T) {
+	ctx := context.Background()
+	svc, database := newTestWebAuthnService(t)
+	user := createTestUser(t, database, "passkey_user")
+	authenticator := newSoftAuthenticator(t)
+
+	creation, err := svc.BeginRegistration(ctx, user.ID)
+	require.NoError(t, err)
+	require.NoError(t, svc.FinishRegistration(ctx, user.ID, &dto.WebAuthnFinishRegistrationRequest{
+		Credential: authenticator.register(t, creation),
+	}))
+
+	begin, err := svc.BeginLogin(ctx, &dto.WebAuthnBeginLoginRequest{Username: user.Username})
+	require.NoError(t, err)
+	credential := authenticator.assert(t, begin.Options)
+	_, err = svc.FinishLogin(ctx, &dto.WebAuthnFinishLoginRequest{CeremonyId: begin.CeremonyId, Credential: credential})
+	require.NoError(t, err)
+
+	_, err = svc.FinishLogin(ctx, &dto.WebAuthnFinishLoginRequest{CeremonyId: begin.CeremonyId, Credential: credential})
+	var se *service_errors.ServiceError
+	require.True(t, errors.As(err, &se))
+	assert.Equal(t, service_errors.WebAuthnChallengeNotFound, se.EndUserMessage)
+}
+
+func TestWebAuthnRejectsForeignSignature(t *testing.T) {
+	ctx := context.Background()
+	svc, database := newTestWebAuthnService(t)
+	user := createTestUser(t, database, "passkey_user")
+	authenticator := newSoftAuthenticator(t)
+
+	creation, err := svc.BeginRegistration(ctx, user.ID)
+	require.NoError(t, err)
+	require.NoError(t, svc.FinishRegistration(ctx, user.ID, &dto.WebAuthnFinishRegistrationRequest{
+		Credential: authenticator.register(t, creation),
+	}))
+
+	begin, err := svc.BeginLogin(ctx, &dto.WebAuthnBeginLoginRequest{Username: user.Username})
+	require.NoError(t, err)
+	impostor := newSoftAuthenticator(t)
+	impostor.credentialId = authenticator.credentialId
+	_, err = svc.FinishLogin(ctx, &dto.WebAuthnFinishLoginRequest{
+		CeremonyId: begin.CeremonyId,
+		Credential: impostor.assert(t, begin.Options),
+	})
+	var se *service_errors.ServiceError
+	require.True(t, errors.As(err, &se))
+	assert.Equal(t, service_errors.WebAuthnVerificationFailed, se.EndUserMessage)
+
+	auditLogger := svc.audit.(*fakeAuditLogger)
+	assert.Equal(t, []audit.Action{audit.LoginFailed}, auditLogger.actions())
+	assert.Equal(t, user.ID, auditLogger.events[0].UserId)
+}
+
+func TestWebAuthnConcurrentLoginsKeepTheirOwnChallenge(t *testing.T) {
+	ctx := context.Background()
+	svc, database := newTestWebAuthnService(t)
+	user := createTestUser(t, database, "passkey_user")
+	authenticator := newSoftAuthenticator(t)
+
+	creation, err := svc.BeginRegistration(ctx, user.ID)
+	require.NoError(t, err)
+	require.NoError(t, svc.FinishRegistration(ctx, user.ID, &dto.WebAuthnFinishRegistrationRequest{
+		Credential: authenticator.register(t, creation),
+	}))
+
+	first, err := svc.BeginLogin(ctx, &dto.WebAuthnBeginLoginRequest{Username: user.Username})
+	require.NoError(t, err)
+	second, err := svc.BeginLogin(ctx, &dto.WebAuthnBeginLoginRequest{Username: user.Username})
+	require.NoError(t, err)
+	assert.NotEqual(t, first.CeremonyId, second.CeremonyId)
+
+	_, err = svc.FinishLogin(ctx, &dto.WebAuthnFinishLoginRequest{
+		CeremonyId: first.CeremonyId,
+		Credential: authenticator.assert(t, first.Options),
+	})
+	require.NoError(t, err)
+	_, err = svc.FinishLogin(ctx, &dto.WebAuthnFinishLoginRequest{
+		CeremonyId: second.CeremonyId,
+		Credential: authenticator.assert(t, second.Options),
+	})
+	require.NoError(t, err)
+}
+
+func TestWebAuthnBeginLoginDoesNotRevealAccounts(t *testing.T) {
+	ctx := context.Background()
+	svc, database := newTestWebAuthnService(t)
+	owner := createTestUser(t, database, "passkey_user")
+	bare := createTestUser(t, database, "bare_user")
+	authenticator := newSoftAuthenticator(t)
+	creation, err := svc.BeginRegistration(ctx, owner.ID)
+	require.NoError(t, err)
+	require.NoError(t, svc.FinishRegistration(ctx, owner.ID, &dto.WebAuthnFinishRegistrationRequest{
+		Name:       "laptop",
+		Credential: authenticator.register(t, creation),
+	}))
+
+	unknown, err := svc.BeginLogin(ctx, &dto.WebAuthnBeginLoginRequest{Username: "nobody"})
+	require.NoError(t, err)
+	assert.Empty(t, unknown.Options.Response.AllowedCredentials)
+	withoutPasskey, err := svc.BeginLogin(ctx, &dto.WebAuthnBeginLoginRequest{Username: bare.Username})
+	require.NoError(t, err)
+	assert.Empty(t, withoutPasskey.Options.Response.AllowedCredentials)
+
+	stranger := newSoftAuthenticator(t)
+	stranger.userHandle = []byte(strconv.FormatUint(uint64(bare.ID), 10))
+	_, err = svc.FinishLogin(ctx, &dto.WebAuthnFinishLoginRequest{
+		CeremonyId: withoutPasskey.CeremonyId,
+		Credential: stranger.assert(t, withoutPasskey.Options),
+	})
+	assertServiceError(t, err, service_errors.WebAuthnVerificationFailed)
+
+	// A discoverable challenge is still answerable by a registered passkey, which signs in its own user.
+	authenticator.userHandle = []byte(strconv.FormatUint(uint64(owner.ID), 10))
+	token, err := svc.FinishLogin(ctx, &dto.WebAuthnFinishLoginRequest{
+		CeremonyId: unknown.CeremonyId,
+		Credential: authenticator.assert(t, unknown.Options),
+	})
+	require.NoError(t, err)
+	assert.NotEmpty(t, token.AccessToken)
+}
@@ -4,6 +4,7 @@ import (
 	"base_structure/src/api/dto"
 	"base_structure/src/config"
 	"base_structure/src/constants"
+	"base_structure/src/data/models"
 	"base_structure/src/pkg/logging"
 	"base_structure/src/pkg/service_errors"
 	"github.com/golang-jwt/jwt"
@@ -25,6 +26,23 @@ type tokenDto struct {
 	Roles        []string
 }
 
+func newTokenDto(user *models.User) *tokenDto {
+	td := &tokenDto{
+		UserId:       user.ID,
+		FirstName:    user.FirstName,
+		LastName:     user.LastName,
+		Username:     user.Username,
+		MobileNumber: user.MobileNumber,
+		Email:        user.Email,
+	}
+	if user.RoleUsers != nil {
+		for _, ru := range *user.RoleUsers {
+			td.Roles = append(td.Roles, ru.Role.Name)
+		}
+	}
+	return td
+}
+
 func NewTokenService(cfg *config.Config) *TokenService {
 	logger := logging.NewLogger(cfg)
 	return &TokenService{logger: logger, cfg: cfg}
@@ -64,7 +82,7 @@ func (s *TokenService) VerifyToken(token string) (*jwt.Token, error) {
 	at, err := jwt.Parse(token, func(token *jwt.Token) (interface{}, error) {
 		_, ok := token.Method.(*jwt.SigningMethodHMAC)
 		if !ok {
-			return nil, &service_errors.ServiceError{EndUserMessage: service_errors.UnexpectedError}
+			return nil, service_errors.New(service_errors.ErrUnexpected)
 		}
 		return []byte(s.cfg.Jwt.Secret), nil
 	})
@@ -87,14 +105,14 @@ func (s *TokenService) GetClaims(token string) (claimMap map[string]interface{},
 		}
 		return claimMap, nil
 	}
-	return nil, &service_errors.ServiceError{EndUserMessage: service_errors.ClaimsNotFound}
+	return nil, service_errors.New(service_errors.ErrClaimsNotFound)
 }
 
 func (s *TokenService) VerifyRefreshToken(token string) (*jwt.Token, error) {
 	return jwt.Parse(token, func(t *jwt.Token) (interface{}, error) {
 		_, ok := t.Method.(*jwt.SigningMethodHMAC)
 		if !ok {
-			return nil, &service_errors.ServiceError{EndUserMessage: service_errors.UnexpectedError}
+			return nil, service_errors.New(service_errors.ErrUnexpected)
 		}
 		return []byte(s.cfg.Jwt.RefreshSecret), nil
 	})
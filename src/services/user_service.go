@@ -7,10 +7,17 @@ import (
 	"base_structure/src/constants"
 	"base_structure/src/data/db"
 	"base_structure/src/data/models"
+	"base_structure/src/data/repository"
+	"base_structure/src/pkg/audit"
 	"base_structure/src/pkg/logging"
+	"base_structure/src/pkg/metrics"
 	"base_structure/src/pkg/service_errors"
+	"base_structure/src/pkg/tracing"
+	"context"
+	"errors"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
+	"strconv"
 )
 
 type UserService struct {
@@ -18,7 +25,10 @@ type UserService struct {
 	cfg          *config.Config
 	otpService   *OtpService
 	tokenService *TokenService
-	database     *gorm.DB
+	audit        AuditLoggerIface
+	uow          db.UnitOfWork
+	users        repository.UserRepository
+	roles        repository.RoleRepository
 }
 
 func NewUserService(cfg *config.Config) *UserService {
@@ -26,244 +36,188 @@ func NewUserService(cfg *config.Config) *UserService {
 	logger := logging.NewLogger(cfg)
 	return &UserService{
 		cfg:          cfg,
-		database:     database,
 		logger:       logger,
 		otpService:   NewOtpService(cfg),
 		tokenService: NewTokenService(cfg),
+		audit:        GetAuditLogger(cfg),
+		uow:          db.NewUnitOfWork(database),
+		users:        repository.NewUserRepository(database),
+		roles:        repository.NewRoleRepository(database),
 	}
 }
 
-func (s *UserService) RegisterByUsername(req *dto.RegisterByUsernameRequest) error {
+func (s *UserService) RegisterByUsername(ctx context.Context, req *dto.RegisterByUsernameRequest) (err error) {
+	ctx, span := tracing.Start(ctx, "UserService.RegisterByUsername")
+	defer func() { tracing.End(span, err) }()
 	u := models.User{Username: req.Username, FirstName: req.FirstName, LastName: req.LastName, Email: req.Email}
-	exists, err := s.existsByEmail(req.Email)
+	hp, err := hashPassword(ctx, req.Password)
 	if err != nil {
-		return err
-	}
-	if exists {
-		return &service_errors.ServiceError{EndUserMessage: service_errors.EmailExists}
-	}
-	exists, err = s.existsByUsername(req.Username)
-	if err != nil {
-		return err
-	}
-	if exists {
-		return &service_errors.ServiceError{EndUserMessage: service_errors.UsernameExists}
-	}
-	bp := []byte(req.Password)
-	hp, err := bcrypt.GenerateFromPassword(bp, bcrypt.DefaultCost)
-	if err != nil {
-		s.logger.Error(logging.General, logging.HashPassword, err.Error(), nil)
+		s.logger.WithContext(ctx).Error(logging.General, logging.HashPassword, err.Error(), nil)
 		return err
 	}
 	u.Password = string(hp)
-	roleId, err := s.getDefaultRole()
-	if err != nil {
-		s.logger.Error(logging.Postgres, logging.DefaultRoleNotFound, err.Error(), nil)
-		return err
-	}
-	tx := s.database.Begin()
-	err = tx.Create(&u).Error
-	if err != nil {
-		tx.Rollback()
-		s.logger.Error(logging.Postgres, logging.Rollback, err.Error(), nil)
-		return err
-	}
-	err = tx.Create(&models.RoleUser{RoleId: roleId, UserId: u.ID}).Error
+	err = s.uow.WithTransaction(ctx, func(ctx context.Context) error {
+		exists, err := s.users.ExistsByEmail(ctx, req.Email)
+		if err != nil {
+			s.logger.WithContext(ctx).Error(logging.Postgres, logging.Select, err.Error(), nil)
+			return err
+		}
+		if exists {
+			return service_errors.New(service_errors.ErrEmailExists)
+		}
+		exists, err = s.users.ExistsByUsername(ctx, req.Username)
+		if err != nil {
+			s.logger.WithContext(ctx).Error(logging.Postgres, logging.Select, err.Error(), nil)
+			return err
+		}
+		if exists {
+			return service_errors.New(service_errors.ErrUsernameExists)
+		}
+		roleId, err := s.getDefaultRole(ctx)
+		if err != nil {
+			s.logger.WithContext(ctx).Error(logging.Postgres, logging.DefaultRoleNotFound, err.Error(), nil)
+			return err
+		}
+		err = s.users.CreateWithRole(ctx, &u, roleId)
+		if err != nil {
+			s.logger.WithContext(ctx).Error(logging.Postgres, logging.Rollback, err.Error(), nil)
+			return err
+		}
+		return nil
+	})
 	if err != nil {
-		tx.Rollback()
-		s.logger.Error(logging.Postgres, logging.Rollback, err.Error(), nil)
 		return err
 	}
-	tx.Commit()
+	s.auditUser(ctx, audit.Register, u.ID, &u)
 	return nil
 }
 
-func (s *UserService) RegisterLoginByMobileNumber(req *dto.RegisterLoginByMobileRequest) (*dto.TokenDetail, error) {
-	err := s.otpService.ValidateOtp(req.MobileNumber, req.Otp)
-	if err != nil {
-		return nil, err
-	}
-	exists, err := s.existsByMobileNumber(req.MobileNumber)
-	if err != nil {
-		return nil, err
-	}
+func (s *UserService) RegisterLoginByMobileNumber(ctx context.Context, req *dto.RegisterLoginByMobileRequest) (_ *dto.TokenDetail, err error) {
+	ctx, span := tracing.Start(ctx, "UserService.RegisterLoginByMobileNumber")
+	defer func() {
+		tracing.End(span, err)
+		metrics.RecordLogin("mobile", err)
+	}()
 	u := models.User{MobileNumber: req.MobileNumber, Username: req.MobileNumber}
-	if exists {
-		var user models.User
-		err = s.database.
-			Model(&models.User{}).
-			Where("mobile_number = ?", u.MobileNumber).
-			Preload("RoleUsers", func(tx *gorm.DB) *gorm.DB {
-				return tx.Preload("Role")
-			}).
-			First(&user).Error
+	var created bool
+	var user *models.User
+	var token *dto.TokenDetail
+	err = s.uow.WithTransaction(ctx, func(ctx context.Context) error {
+		err := s.otpService.ValidateOtp(ctx, req.MobileNumber, req.Otp)
 		if err != nil {
-			return nil, err
+			if service_errors.KindOf(err) != service_errors.ErrInternal {
+				s.audit.Log(ctx, AuditEvent{Action: audit.OtpFailed, TargetType: audit.TargetMobile, TargetId: req.MobileNumber})
+			}
+			return err
 		}
-		tokenDto := tokenDto{
-			UserId:       user.ID,
-			FirstName:    user.FirstName,
-			LastName:     user.LastName,
-			Username:     user.Username,
-			MobileNumber: user.MobileNumber,
-			Email:        user.Email,
+		exists, err := s.users.ExistsByMobileNumber(ctx, req.MobileNumber)
+		if err != nil {
+			s.logger.WithContext(ctx).Error(logging.Postgres, logging.Select, err.Error(), nil)
+			return err
 		}
-		if len(*user.RoleUsers) > 0 {
-			for _, ru := range *user.RoleUsers {
-				tokenDto.Roles = append(tokenDto.Roles, ru.Role.Name)
+		if !exists {
+			hp, err := hashPassword(ctx, common.GeneratePassword())
+			if err != nil {
+				s.logger.WithContext(ctx).Error(logging.General, logging.HashPassword, err.Error(), nil)
+				return err
 			}
+			u.Password = string(hp)
+			roleId, err := s.getDefaultRole(ctx)
+			if err != nil {
+				s.logger.WithContext(ctx).Error(logging.Postgres, logging.DefaultRoleNotFound, err.Error(), nil)
+				return err
+			}
+			err = s.users.CreateWithRole(ctx, &u, roleId)
+			if err != nil {
+				s.logger.WithContext(ctx).Error(logging.Postgres, logging.Rollback, err.Error(), nil)
+				return err
+			}
+			created = true
 		}
-		token, err := s.tokenService.GenerateToken(&tokenDto)
+		user, err = s.users.FindByMobileNumber(ctx, u.MobileNumber)
 		if err != nil {
-			return nil, err
+			return err
 		}
-		return token, nil
-	}
-	bp := []byte(common.GeneratePassword())
-	hp, err := bcrypt.GenerateFromPassword(bp, bcrypt.DefaultCost)
-	if err != nil {
-		s.logger.Error(logging.General, logging.HashPassword, err.Error(), nil)
-		return nil, err
-	}
-	u.Password = string(hp)
-	roleId, err := s.getDefaultRole()
-	if err != nil {
-		s.logger.Error(logging.Postgres, logging.DefaultRoleNotFound, err.Error(), nil)
-		return nil, err
-	}
-	tx := s.database.Begin()
-	err = tx.Create(&u).Error
-	if err != nil {
-		tx.Rollback()
-		s.logger.Error(logging.Postgres, logging.Rollback, err.Error(), nil)
-		return nil, err
-	}
-	err = tx.Create(&models.RoleUser{RoleId: roleId, UserId: u.ID}).Error
-	if err != nil {
-		tx.Rollback()
-		s.logger.Error(logging.Postgres, logging.Rollback, err.Error(), nil)
-		return nil, err
-	}
-	tx.Commit()
-	var user models.User
-	err = s.database.
-		Model(&models.User{}).
-		Where("mobile_number = ?", u.MobileNumber).
-		Preload("RoleUsers", func(tx *gorm.DB) *gorm.DB {
-			return tx.Preload("Role")
-		}).
-		First(&user).Error
+		token, err = s.tokenService.GenerateToken(newTokenDto(user))
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
-	tokenDto := tokenDto{
-		UserId:       user.ID,
-		FirstName:    user.FirstName,
-		LastName:     user.LastName,
-		Username:     user.Username,
-		MobileNumber: user.MobileNumber,
-		Email:        user.Email,
-	}
-	if len(*user.RoleUsers) > 0 {
-		for _, ru := range *user.RoleUsers {
-			tokenDto.Roles = append(tokenDto.Roles, ru.Role.Name)
-		}
-	}
-	token, err := s.tokenService.GenerateToken(&tokenDto)
-	if err != nil {
-		return nil, err
+	if created {
+		s.auditUser(ctx, audit.Register, u.ID, &u)
 	}
+	s.auditUser(ctx, audit.Login, user.ID, nil)
 	return token, nil
 }
 
-func (s *UserService) LoginByUsername(req *dto.LoginByUsernameRequest) (*dto.TokenDetail, error) {
-	var user models.User
-	err := s.database.
-		Model(&models.User{}).
-		Where("username = ?", req.Username).
-		Preload("RoleUsers", func(tx *gorm.DB) *gorm.DB {
-			return tx.Preload("Role")
-		}).First(&user).Error
-	if err != nil {
+func (s *UserService) LoginByUsername(ctx context.Context, req *dto.LoginByUsernameRequest) (_ *dto.TokenDetail, err error) {
+	ctx, span := tracing.Start(ctx, "UserService.LoginByUsername")
+	defer func() {
+		tracing.End(span, err)
+		metrics.RecordLogin("username", err)
+	}()
+	user, err := s.users.FindByUsername(ctx, req.Username)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			s.audit.Log(ctx, AuditEvent{Action: audit.LoginFailed, TargetType: audit.TargetUser, TargetId: req.Username})
+		}
 		return nil, err
 	}
-	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password))
+	err = comparePassword(ctx, user.Password, req.Password)
 	if err != nil {
-		return nil, &service_errors.ServiceError{EndUserMessage: service_errors.InvalidCredentials, Err: err}
+		s.auditUser(ctx, audit.LoginFailed, user.ID, nil)
+		return nil, service_errors.Wrap(service_errors.ErrInvalidCredentials, err)
 	}
-	tokenDto := tokenDto{
-		UserId:       user.ID,
-		FirstName:    user.FirstName,
-		LastName:     user.LastName,
-		Username:     user.Username,
-		MobileNumber: user.MobileNumber,
-		Email:        user.Email,
-	}
-	if len(*user.RoleUsers) > 0 {
-		for _, ru := range *user.RoleUsers {
-			tokenDto.Roles = append(tokenDto.Roles, ru.Role.Name)
-		}
-	}
-	token, err := s.tokenService.GenerateToken(&tokenDto)
+	token, err := s.tokenService.GenerateToken(newTokenDto(user))
 	if err != nil {
 		return nil, err
 	}
+	s.auditUser(ctx, audit.Login, user.ID, nil)
 	return token, nil
 }
 
-func (s *UserService) SendOtp(req *dto.GetOtpRequest) error {
+func (s *UserService) SendOtp(ctx context.Context, req *dto.GetOtpRequest) (err error) {
+	ctx, span := tracing.Start(ctx, "UserService.SendOtp")
+	defer func() { tracing.End(span, err) }()
 	otp := common.GenerateOtp()
-	err := s.otpService.SetOtp(req.MobileNumber, otp)
+	err = s.otpService.SetOtp(ctx, req.MobileNumber, otp)
 	if err != nil {
 		return err
 	}
+	metrics.OtpSent.Inc()
+	s.audit.Log(ctx, AuditEvent{Action: audit.OtpSent, TargetType: audit.TargetMobile, TargetId: req.MobileNumber})
 	return nil
 }
 
-func (s *UserService) existsByEmail(email string) (bool, error) {
-	var exists bool
-	if err := s.database.Model(&models.User{}).Select("count(*) > 0").
-		Where("email = ?", email).
-		Find(&exists).
-		Error; err != nil {
-		s.logger.Error(logging.Postgres, logging.Select, err.Error(), nil)
-		return false, err
-	}
-	return exists, nil
+// hashPassword and comparePassword run bcrypt in their own spans, since it is the slowest step of a login.
+func hashPassword(ctx context.Context, password string) (hash []byte, err error) {
+	_, span := tracing.Start(ctx, "bcrypt.GenerateFromPassword")
+	defer func() { tracing.End(span, err) }()
+	return bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 }
 
-func (s *UserService) existsByUsername(username string) (bool, error) {
-	var exists bool
-	if err := s.database.Model(&models.User{}).
-		Select("count(*) > 0").
-		Where("username = ?", username).
-		Find(&exists).
-		Error; err != nil {
-		s.logger.Error(logging.Postgres, logging.Select, err.Error(), nil)
-		return false, err
-	}
-	return exists, nil
+func comparePassword(ctx context.Context, hash string, password string) (err error) {
+	_, span := tracing.Start(ctx, "bcrypt.CompareHashAndPassword")
+	defer func() { tracing.End(span, err) }()
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
 }
 
-func (s *UserService) existsByMobileNumber(mobileNumber string) (bool, error) {
-	var exists bool
-	if err := s.database.Model(&models.User{}).
-		Select("count(*) > 0").
-		Where("mobile_number = ?", mobileNumber).
-		Find(&exists).
-		Error; err != nil {
-		s.logger.Error(logging.Postgres, logging.Select, err.Error(), nil)
-		return false, err
-	}
-	return exists, nil
+// auditUser records an event about userId, who is also the actor unless the request is authenticated.
+func (s *UserService) auditUser(ctx context.Context, action audit.Action, userId uint, after interface{}) {
+	s.audit.Log(ctx, AuditEvent{
+		Action:     action,
+		UserId:     userId,
+		TargetType: audit.TargetUser,
+		TargetId:   strconv.FormatUint(uint64(userId), 10),
+		After:      after,
+	})
 }
 
-func (s *UserService) getDefaultRole() (roleId uint, err error) {
-	if err = s.database.Model(&models.Role{}).
-		Select("id").
-		Where("name = ?", constants.DefaultRoleName).
-		First(&roleId).Error; err != nil {
+func (s *UserService) getDefaultRole(ctx context.Context) (roleId uint, err error) {
+	role, err := s.roles.FindByName(ctx, constants.DefaultRoleName)
+	if err != nil {
 		return 0, err
 	}
-	return roleId, nil
+	return role.ID, nil
 }
@@ -1,10 +1,13 @@
 package services
 
-import "base_structure/src/api/dto"
+import (
+	"base_structure/src/api/dto"
+	"context"
+)
 
 type UserServiceIface interface {
-	SendOtp(req *dto.GetOtpRequest) error
-	LoginByUsername(req *dto.LoginByUsernameRequest) (*dto.TokenDetail, error)
-	RegisterByUsername(req *dto.RegisterByUsernameRequest) error
-	RegisterLoginByMobileNumber(req *dto.RegisterLoginByMobileRequest) (*dto.TokenDetail, error)
+	SendOtp(ctx context.Context, req *dto.GetOtpRequest) error
+	LoginByUsername(ctx context.Context, req *dto.LoginByUsernameRequest) (*dto.TokenDetail, error)
+	RegisterByUsername(ctx context.Context, req *dto.RegisterByUsernameRequest) error
+	RegisterLoginByMobileNumber(ctx context.Context, req *dto.RegisterLoginByMobileRequest) (*dto.TokenDetail, error)
 }
@@ -0,0 +1,324 @@
+package services
+
+import (
+	"base_structure/src/api/dto"
+	"base_structure/src/config"
+	"base_structure/src/constants"
+	"base_structure/src/data/cache"
+	"base_structure/src/data/db"
+	"base_structure/src/data/models"
+	"base_structure/src/data/repository"
+	"base_structure/src/pkg/logging"
+	"base_structure/src/pkg/metrics"
+	"base_structure/src/pkg/service_errors"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/go-redis/redis/v7"
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"gorm.io/gorm"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	webAuthnRegistrationCeremony = "registration"
+	webAuthnLoginCeremony        = "login"
+)
+
+type WebAuthnService struct {
+	logger       logging.Logger
+	cfg          *config.Config
+	redisClient  *redis.Client
+	database     *gorm.DB
+	tokenService *TokenService
+	audit        AuditLoggerIface
+	users        repository.UserRepository
+	webAuthn     *webauthn.WebAuthn
+}
+
+// webAuthnUser adapts models.User to the webauthn.User interface.
+type webAuthnUser struct {
+	user        *models.User
+	credentials []webauthn.Credential
+}
+
+func (u *webAuthnUser) WebAuthnID() []byte {
+	return []byte(strconv.FormatUint(uint64(u.user.ID), 10))
+}
+
+func (u *webAuthnUser) WebAuthnName() string {
+	return u.user.Username
+}
+
+func (u *webAuthnUser) WebAuthnDisplayName() string {
+	name := strings.TrimSpace(u.user.FirstName + " " + u.user.LastName)
+	if name == "" {
+		return u.user.Username
+	}
+	return name
+}
+
+func (u *webAuthnUser) WebAuthnCredentials() []webauthn.Credential {
+	return u.credentials
+}
+
+func NewWebAuthnService(cfg *config.Config) *WebAuthnService {
+	logger := logging.NewLogger(cfg)
+	wa, err := newWebAuthn(cfg)
+	if err != nil {
+		logger.Fatal(logging.Internal, logging.WebAuthn, err.Error(), nil)
+	}
+	database := db.GetDb(cfg)
+	return &WebAuthnService{
+		logger:       logger,
+		cfg:          cfg,
+		redisClient:  cache.GetRedis(cfg),
+		database:     database,
+		tokenService: NewTokenService(cfg),
+		audit:        GetAuditLogger(cfg),
+		users:        repository.NewUserRepository(database),
+		webAuthn:     wa,
+	}
+}
+
+func newWebAuthn(cfg *config.Config) (*webauthn.WebAuthn, error) {
+	return webauthn.New(&webauthn.Config{
+		RPID:          cfg.WebAuthn.RPID,
+		RPDisplayName: cfg.WebAuthn.RPDisplayName,
+		RPOrigins:     cfg.WebAuthn.RPOrigins,
+	})
+}
+
+func (s *WebAuthnService) BeginRegistration(ctx context.Context, userId uint) (*protocol.CredentialCreation, error) {
+	u, err := s.getUser(ctx, repository.Filter("id", userId))
+	if err != nil {
+		return nil, err
+	}
+	exclusions := make([]protocol.CredentialDescriptor, 0, len(u.credentials))
+	for _, c := range u.credentials {
+		exclusions = append(exclusions, c.Descriptor())
+	}
+	creation, session, err := s.webAuthn.BeginRegistration(
+		u,
+		webauthn.WithExclusions(exclusions),
+		webauthn.WithResidentKeyRequirement(protocol.ResidentKeyRequirementPreferred),
+	)
+	if err != nil {
+		s.logger.WithContext(ctx).Error(logging.Internal, logging.WebAuthn, err.Error(), nil)
+		return nil, err
+	}
+	err = s.setSession(ctx, webAuthnRegistrationCeremony, strconv.FormatUint(uint64(userId), 10), session)
+	if err != nil {
+		return nil, err
+	}
+	return creation, nil
+}
+
+func (s *WebAuthnService) FinishRegistration(ctx context.Context, userId uint, req *dto.WebAuthnFinishRegistrationRequest) error {
+	u, err := s.getUser(ctx, repository.Filter("id", userId))
+	if err != nil {
+		return err
+	}
+	session, err := s.popSession(ctx, webAuthnRegistrationCeremony, strconv.FormatUint(uint64(userId), 10))
+	if err != nil {
+		return err
+	}
+	parsed, err := protocol.ParseCredentialCreationResponseBytes(req.Credential)
+	if err != nil {
+		return service_errors.Wrap(service_errors.ErrWebAuthnVerificationFailed, err)
+	}
+	credential, err := s.webAuthn.CreateCredential(u, *session, parsed)
+	if err != nil {
+		return service_errors.Wrap(service_errors.ErrWebAuthnVerificationFailed, err)
+	}
+	transports := make([]string, 0, len(credential.Transport))
+	for _, t := range credential.Transport {
+		transports = append(transports, string(t))
+	}
+	m := models.WebAuthnCredential{
+		UserId:          userId,
+		Name:            req.Name,
+		CredentialId:    credential.ID,
+		PublicKey:       credential.PublicKey,
+		AttestationType: credential.AttestationType,
+		Transports:      strings.Join(transports, ","),
+		Aaguid:          credential.Authenticator.AAGUID,
+		SignCount:       credential.Authenticator.SignCount,
+		Flags:           uint8(credential.Flags.ProtocolValue()),
+	}
+	err = db.Conn(ctx, s.database).Create(&m).Error
+	if err != nil {
+		s.logger.WithContext(ctx).Error(logging.Postgres, logging.Insert, err.Error(), nil)
+		return err
+	}
+	return nil
+}
+
+// BeginLogin starts an assertion ceremony and keys its challenge by a random ceremony id, which the client echoes back
+// to FinishLogin, so concurrent logins for the same username do not overwrite each other. Unknown usernames and users
+// without passkeys get a discoverable challenge instead of an error, so the response tells neither apart.
+func (s *WebAuthnService) BeginLogin(ctx context.Context, req *dto.WebAuthnBeginLoginRequest) (*dto.WebAuthnBeginLoginResponse, error) {
+	u, err := s.getUser(ctx, repository.Filter("username", req.Username))
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	var assertion *protocol.CredentialAssertion
+	var session *webauthn.SessionData
+	if err == nil && len(u.credentials) > 0 {
+		assertion, session, err = s.webAuthn.BeginLogin(u)
+	} else {
+		assertion, session, err = s.webAuthn.BeginDiscoverableLogin()
+	}
+	if err != nil {
+		s.logger.WithContext(ctx).Error(logging.Internal, logging.WebAuthn, err.Error(), nil)
+		return nil, err
+	}
+	ceremonyId, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+	err = s.setSession(ctx, webAuthnLoginCeremony, ceremonyId, session)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.WebAuthnBeginLoginResponse{CeremonyId: ceremonyId, Options: assertion}, nil
+}
+
+// FinishLogin verifies the assertion against the user the challenge was issued for, or, for a discoverable challenge,
+// against the user named by the credential's user handle.
+func (s *WebAuthnService) FinishLogin(ctx context.Context, req *dto.WebAuthnFinishLoginRequest) (_ *dto.TokenDetail, err error) {
+	var userId uint
+	defer func() {
+		metrics.RecordLogin("webauthn", err)
+		auditLogin(ctx, s.audit, userId, err)
+	}()
+	session, err := s.popSession(ctx, webAuthnLoginCeremony, req.CeremonyId)
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := protocol.ParseCredentialRequestResponseBytes(req.Credential)
+	if err != nil {
+		return nil, service_errors.Wrap(service_errors.ErrWebAuthnVerificationFailed, err)
+	}
+	var u *webAuthnUser
+	var credential *webauthn.Credential
+	if len(session.UserID) == 0 {
+		credential, err = s.webAuthn.ValidateDiscoverableLogin(func(_, userHandle []byte) (webauthn.User, error) {
+			found, err := s.userByHandle(ctx, userHandle)
+			if err != nil {
+				return nil, err
+			}
+			u, userId = found, found.user.ID
+			return u, nil
+		}, *session, parsed)
+	} else {
+		u, err = s.userByHandle(ctx, session.UserID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, service_errors.Wrap(service_errors.ErrWebAuthnVerificationFailed, err)
+		} else if err != nil {
+			return nil, err
+		}
+		userId = u.user.ID
+		credential, err = s.webAuthn.ValidateLogin(u, *session, parsed)
+	}
+	if err != nil {
+		return nil, service_errors.Wrap(service_errors.ErrWebAuthnVerificationFailed, err)
+	}
+	err = db.Conn(ctx, s.database).
+		Model(&models.WebAuthnCredential{}).
+		Where("credential_id = ?", credential.ID).
+		Updates(map[string]interface{}{
+			"sign_count":    credential.Authenticator.SignCount,
+			"flags":         uint8(parsed.Response.AuthenticatorData.Flags),
+			"clone_warning": credential.Authenticator.CloneWarning,
+		}).Error
+	if err != nil {
+		s.logger.WithContext(ctx).Error(logging.Postgres, logging.Update, err.Error(), nil)
+		return nil, err
+	}
+	if credential.Authenticator.CloneWarning {
+		s.logger.WithContext(ctx).Warn(logging.Internal, logging.WebAuthn, "possible cloned authenticator", nil)
+		return nil, service_errors.New(service_errors.ErrWebAuthnVerificationFailed)
+	}
+	return s.tokenService.GenerateToken(newTokenDto(u.user))
+}
+
+// userByHandle returns the user whose WebAuthnID is handle.
+func (s *WebAuthnService) userByHandle(ctx context.Context, handle []byte) (*webAuthnUser, error) {
+	id, err := strconv.ParseUint(string(handle), 10, 64)
+	if err != nil {
+		return nil, service_errors.Wrap(service_errors.ErrWebAuthnVerificationFailed, err)
+	}
+	return s.getUser(ctx, repository.Filter("id", uint(id)))
+}
+
+func (s *WebAuthnService) getUser(ctx context.Context, opts ...repository.QueryOption) (*webAuthnUser, error) {
+	opts = append(opts, repository.WithRoles(), repository.Preload("WebAuthnCredentials"))
+	user, err := s.users.FindOne(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	u := &webAuthnUser{user: user}
+	if user.WebAuthnCredentials != nil {
+		for _, c := range *user.WebAuthnCredentials {
+			u.credentials = append(u.credentials, toWebAuthnCredential(c))
+		}
+	}
+	return u, nil
+}
+
+func toWebAuthnCredential(m models.WebAuthnCredential) webauthn.Credential {
+	var transports []protocol.AuthenticatorTransport
+	if m.Transports != "" {
+		for _, t := range strings.Split(m.Transports, ",") {
+			transports = append(transports, protocol.AuthenticatorTransport(t))
+		}
+	}
+	return webauthn.Credential{
+		ID:              m.CredentialId,
+		PublicKey:       m.PublicKey,
+		AttestationType: m.AttestationType,
+		Transport:       transports,
+		Flags:           webauthn.NewCredentialFlags(protocol.AuthenticatorFlags(m.Flags)),
+		Authenticator: webauthn.Authenticator{
+			AAGUID:       m.Aaguid,
+			SignCount:    m.SignCount,
+			CloneWarning: m.CloneWarning,
+		},
+	}
+}
+
+func (s *WebAuthnService) sessionKey(ceremony string, id string) string {
+	return fmt.Sprintf("%s:%s:%s", constants.RedisWebAuthnKey, ceremony, id)
+}
+
+func (s *WebAuthnService) setSession(ctx context.Context, ceremony string, id string, session *webauthn.SessionData) error {
+	err := cache.Set(ctx, s.redisClient, s.sessionKey(ceremony, id), session, s.cfg.WebAuthn.ChallengeExpireTime*time.Second)
+	if err != nil {
+		s.logger.WithContext(ctx).Error(logging.Redis, logging.WebAuthn, err.Error(), nil)
+		return err
+	}
+	return nil
+}
+
+// popSession returns the stored challenge and deletes it, so each challenge can be answered only once.
+func (s *WebAuthnService) popSession(ctx context.Context, ceremony string, id string) (*webauthn.SessionData, error) {
+	key := s.sessionKey(ceremony, id)
+	session, err := cache.Get[webauthn.SessionData](ctx, s.redisClient, key)
+	if errors.Is(err, redis.Nil) {
+		return nil, service_errors.New(service_errors.ErrWebAuthnChallengeNotFound)
+	} else if err != nil {
+		return nil, err
+	}
+	n, err := s.redisClient.WithContext(ctx).Del(key).Result()
+	if err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		return nil, service_errors.New(service_errors.ErrWebAuthnChallengeNotFound)
+	}
+	return &session, nil
+}
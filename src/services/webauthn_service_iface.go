@@ -0,0 +1,14 @@
+package services
+
+import (
+	"base_structure/src/api/dto"
+	"context"
+	"github.com/go-webauthn/webauthn/protocol"
+)
+
+type WebAuthnServiceIface interface {
+	BeginRegistration(ctx context.Context, userId uint) (*protocol.CredentialCreation, error)
+	FinishRegistration(ctx context.Context, userId uint, req *dto.WebAuthnFinishRegistrationRequest) error
+	BeginLogin(ctx context.Context, req *dto.WebAuthnBeginLoginRequest) (*dto.WebAuthnBeginLoginResponse, error)
+	FinishLogin(ctx context.Context, req *dto.WebAuthnFinishLoginRequest) (*dto.TokenDetail, error)
+}